@@ -0,0 +1,136 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"io/ioutil"
+)
+
+// embeddedAnswers are the accepted answers for the puzzle inputs embedded
+// from input/, which turns a plain run of the binary into a regression test
+// against real puzzle inputs rather than just the examples in the tests.
+//
+//go:embed answers.json
+var embeddedAnswers []byte
+
+// Verdict is the outcome of checking an answer against the expected one.
+// The zero Verdict means there was nothing to check it against.
+type Verdict int
+
+const (
+	Unknown Verdict = iota
+	Pass
+	Fail
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Pass:
+		return "pass"
+	case Fail:
+		return "fail"
+	default:
+		return "unknown"
+	}
+}
+
+// Expected holds the accepted answers to both parts of a day. Either part
+// may be left empty if it hasn't been solved yet.
+type Expected struct {
+	PartOne string `json:"part_one,omitempty"`
+	PartTwo string `json:"part_two,omitempty"`
+}
+
+// Answers maps each day onto its expected answers.
+type Answers map[int]Expected
+
+func ParseAnswers(b []byte) (Answers, error) {
+	answers := make(Answers)
+	if err := json.Unmarshal(b, &answers); err != nil {
+		return nil, err
+	}
+
+	return answers, nil
+}
+
+// LoadAnswers returns the answers file given with -answers. Without one we
+// fall back on the embedded answers, but only when we're also using the
+// embedded inputs, since someone else's input has its own answers. The same
+// goes for any day whose parameters were overridden with -param.
+func (o Options) LoadAnswers() (Answers, error) {
+	if o.Answers != "" {
+		b, err := ioutil.ReadFile(o.Answers)
+		if err != nil {
+			return nil, err
+		}
+
+		return ParseAnswers(b)
+	}
+
+	if o.Input != "" {
+		return make(Answers), nil
+	}
+
+	answers, err := ParseAnswers(embeddedAnswers)
+	if err != nil {
+		return nil, err
+	}
+
+	for day := range o.Params {
+		delete(answers, day)
+	}
+
+	return answers, nil
+}
+
+// Check marks both parts of the result as passing or failing against the
+// expected answers for its day. Parts that weren't run, that errored, or
+// that have no expected answer are left Unknown.
+func (a Answers) Check(r *Result) {
+	expected, ok := a[r.Day]
+	if !ok {
+		return
+	}
+
+	r.PartOne.check(expected.PartOne)
+	r.PartTwo.check(expected.PartTwo)
+}
+
+func (a *Answer) check(expected string) {
+	if expected == "" || a.Err != nil || a.Value == "" {
+		return
+	}
+
+	a.Expected = expected
+	a.Verdict = Fail
+	if a.Value == expected {
+		a.Verdict = Pass
+	}
+}
+
+// Verdict sums up both parts of the day: it fails if either part failed and
+// only passes if every part that was checked passed.
+func (r Result) Verdict() Verdict {
+	verdict := Unknown
+	for _, answer := range []Answer{r.PartOne, r.PartTwo} {
+		switch answer.Verdict {
+		case Fail:
+			return Fail
+		case Pass:
+			verdict = Pass
+		}
+	}
+
+	return verdict
+}
+
+// Mismatched reports whether any day in the report got a wrong answer.
+func (r Report) Mismatched() bool {
+	for _, result := range r.Results {
+		if result.Verdict() == Fail {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+// Stats summarizes a set of timing samples.
+type Stats struct {
+	Min    time.Duration
+	Median time.Duration
+	Mean   time.Duration
+	P95    time.Duration
+	StdDev time.Duration
+}
+
+func NewStats(samples []time.Duration) Stats {
+	if len(samples) == 0 {
+		return Stats{}
+	}
+
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	var sum float64
+	for _, sample := range sorted {
+		sum += float64(sample)
+	}
+
+	mean := sum / float64(len(sorted))
+
+	var variance float64
+	for _, sample := range sorted {
+		variance += (float64(sample) - mean) * (float64(sample) - mean)
+	}
+
+	variance /= float64(len(sorted))
+
+	n := len(sorted)
+	median := sorted[n/2]
+	if n%2 == 0 {
+		median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+
+	// Nearest rank percentile: the smallest sample that at least 95%
+	// of all the samples are less than or equal to.
+	p95 := sorted[int(math.Ceil(0.95*float64(n)))-1]
+
+	return Stats{
+		Min:    sorted[0],
+		Median: median,
+		Mean:   time.Duration(mean),
+		P95:    p95,
+		StdDev: time.Duration(math.Sqrt(variance)),
+	}
+}
+
+// Benchmark is what we learn about a day by running it over and over.
+type Benchmark struct {
+	Runs    int
+	Load    Stats
+	PartOne Stats
+	PartTwo Stats
+	Total   Stats
+
+	// AllocsPerRun and BytesPerRun are the average number of heap
+	// allocations and bytes allocated by a single run of the day.
+	AllocsPerRun uint64
+	BytesPerRun  uint64
+}
+
+// BenchDay solves the puzzle at least opts.BenchRuns times and for at least
+// opts.BenchTime, whichever takes longer. The returned Result carries the
+// answers from the final run alongside the statistics from all of them.
+//
+// Every run gets its own Solution from the registry so that nothing Load
+// squirrels away can leak from one iteration into the next.
+func BenchDay(p registry.Puzzle, opts Options) Result {
+	input, err := opts.ReadInput(p)
+	if err != nil {
+		result := Result{
+			Day:  p.Day,
+			Name: p.Name(),
+		}
+
+		result.fail(err, opts)
+
+		return result
+	}
+
+	var (
+		result             Result
+		load, one, two     []time.Duration
+		total              []time.Duration
+		before, after      runtime.MemStats
+		mallocs, allocated uint64
+	)
+
+	start := time.Now()
+	for len(total) < opts.BenchRuns || time.Since(start) < opts.BenchTime {
+		sol, err := NewSolution(p, opts)
+		if err != nil {
+			result := Result{
+				Day:  p.Day,
+				Name: p.Name(),
+			}
+
+			result.fail(err, opts)
+
+			return result
+		}
+
+		runtime.ReadMemStats(&before)
+		result = Solve(p.Day, sol, input, opts)
+		runtime.ReadMemStats(&after)
+
+		// A broken day isn't going to get any less broken by
+		// running it again.
+		if result.Failed() {
+			return result
+		}
+
+		mallocs += after.Mallocs - before.Mallocs
+		allocated += after.TotalAlloc - before.TotalAlloc
+
+		load = append(load, result.Timings.Load)
+		one = append(one, result.Timings.PartOne)
+		two = append(two, result.Timings.PartTwo)
+		total = append(total, result.Duration)
+	}
+
+	runs := len(total)
+	result.Bench = &Benchmark{
+		Runs:         runs,
+		Load:         NewStats(load),
+		PartOne:      NewStats(one),
+		PartTwo:      NewStats(two),
+		Total:        NewStats(total),
+		AllocsPerRun: mallocs / uint64(runs),
+		BytesPerRun:  allocated / uint64(runs),
+	}
+
+	return result
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/stntngo/advent-2021/go/registry"
 )
 
 var testCase = `199
@@ -43,3 +45,17 @@ func Test_SlidingWindow(t *testing.T) {
 	assert.Equal(t, 5, reading.SlidingWindow(3).DepthIncrease())
 
 }
+
+func Test_WindowParam(t *testing.T) {
+	var solution Solution
+	require.NoError(t, solution.Load(strings.NewReader(testCase)))
+
+	solution.SetArgs(registry.Args{"window": 0})
+	_, err := solution.PartTwo()
+	assert.Error(t, err)
+
+	solution.SetArgs(registry.Args{"window": 1})
+	ans, err := solution.PartTwo()
+	require.NoError(t, err)
+	assert.Equal(t, "7", ans)
+}
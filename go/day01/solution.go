@@ -1,18 +1,49 @@
 package day01
 
 import (
+	"errors"
 	"io"
 	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  1,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
+var _WINDOW = registry.Param{
+	Name:    "window",
+	Usage:   "size of the sliding window in part two",
+	Default: 3,
+	Check:   registry.AtLeast(1),
+}
+
 type Solution struct {
 	reading SonarReading
+	args    registry.Args
 }
 
 func (s *Solution) Name() string {
 	return "Sonar Sweep"
 }
 
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
+func (s *Solution) Params() []registry.Param {
+	return []registry.Param{_WINDOW}
+}
+
+func (s *Solution) SetArgs(args registry.Args) {
+	s.args = args
+}
+
 func (s *Solution) Load(r io.Reader) error {
 	reading, err := ParseSonarReading(r)
 	if err != nil {
@@ -29,5 +60,27 @@ func (s *Solution) PartOne() (string, error) {
 }
 
 func (s *Solution) PartTwo() (string, error) {
-	return strconv.Itoa(s.reading.SlidingWindow(3).DepthIncrease()), nil
+	window := s.args.Int(_WINDOW)
+	if window < 1 {
+		return "", errors.New("window must be at least 1")
+	}
+
+	return strconv.Itoa(s.reading.SlidingWindow(window).DepthIncrease()), nil
+}
+
+func (s *Solution) Operations() []registry.Operation {
+	return []registry.Operation{
+		{
+			Name:  "SlidingWindow",
+			Args:  []string{"size"},
+			Usage: "count the depth increases between sliding windows of the given size",
+			Call: func(args []int) (string, error) {
+				if args[0] < 1 {
+					return "", errors.New("size must be at least 1")
+				}
+
+				return strconv.Itoa(s.reading.SlidingWindow(args[0]).DepthIncrease()), nil
+			},
+		},
+	}
 }
@@ -3,8 +3,18 @@ package day02
 import (
 	"io"
 	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  2,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
 type Solution struct {
 	commands []Command
 }
@@ -13,6 +23,10 @@ func (s *Solution) Name() string {
 	return "Dive!"
 }
 
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
 func (s *Solution) Load(r io.Reader) error {
 	commands, err := ParseCommands(r)
 	if err != nil {
@@ -3,8 +3,18 @@ package day03
 import (
 	"fmt"
 	"io"
+
+	"github.com/stntngo/advent-2021/go/registry"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  3,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
 type Solution struct {
 	lines [][]string
 }
@@ -13,6 +23,10 @@ func (s *Solution) Name() string {
 	return "Binary Diagnostic"
 }
 
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
 func (s *Solution) Load(r io.Reader) error {
 	lines, err := Parse(r)
 	if err != nil {
@@ -3,8 +3,18 @@ package day04
 import (
 	"io"
 	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  4,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
 type Solution struct {
 	rand   *RandomNumbers
 	boards []Board
@@ -6,6 +6,8 @@ import (
 	"io"
 	"strconv"
 	"strings"
+
+	"github.com/stntngo/advent-2021/go/grid"
 )
 
 type LineType int
@@ -16,12 +18,8 @@ const (
 	Diagonal
 )
 
-type Point struct {
-	X, Y int
-}
-
-func ParsePoint(s string) (Point, error) {
-	var point Point
+func ParsePoint(s string) (grid.Point, error) {
+	var point grid.Point
 
 	parts := strings.Split(s, ",")
 	if len(parts) != 2 {
@@ -45,7 +43,7 @@ func ParsePoint(s string) (Point, error) {
 }
 
 type Line struct {
-	Start, End Point
+	Start, End grid.Point
 }
 
 func (l *Line) LineType() LineType {
@@ -60,7 +58,7 @@ func (l *Line) LineType() LineType {
 	return Diagonal
 }
 
-func (l *Line) Points() []Point {
+func (l *Line) Points() []grid.Point {
 	switch l.LineType() {
 	case Horizontal:
 		return l.horizontalLine()
@@ -73,41 +71,41 @@ func (l *Line) Points() []Point {
 	}
 }
 
-func (l *Line) verticalLine() []Point {
+func (l *Line) verticalLine() []grid.Point {
 	start, end := l.Start, l.End
 	if start.Y > end.Y {
 		start, end = end, start
 	}
 
-	points := make([]Point, 0)
+	points := make([]grid.Point, 0)
 	for i := start.Y; i <= end.Y; i++ {
-		points = append(points, Point{start.X, i})
+		points = append(points, grid.Point{X: start.X, Y: i})
 	}
 
 	return points
 }
 
-func (l *Line) horizontalLine() []Point {
+func (l *Line) horizontalLine() []grid.Point {
 	start, end := l.Start, l.End
 	if start.X > end.X {
 		start, end = end, start
 	}
 
-	points := make([]Point, 0)
+	points := make([]grid.Point, 0)
 	for i := start.X; i <= end.X; i++ {
-		points = append(points, Point{i, start.Y})
+		points = append(points, grid.Point{X: i, Y: start.Y})
 	}
 
 	return points
 }
 
-func (l *Line) diagonaLine() []Point {
+func (l *Line) diagonaLine() []grid.Point {
 	start, end := l.Start, l.End
 	if start.X > end.X {
 		start, end = end, start
 	}
 
-	points := make([]Point, 0)
+	points := make([]grid.Point, 0)
 
 	y := start.Y
 	yFunc := func(y int) int {
@@ -121,7 +119,7 @@ func (l *Line) diagonaLine() []Point {
 	}
 
 	for i := start.X; i <= end.X; i++ {
-		points = append(points, Point{i, y})
+		points = append(points, grid.Point{X: i, Y: y})
 
 		y = yFunc(y)
 	}
@@ -183,7 +181,7 @@ func Parse(r io.Reader) ([]Line, error) {
 // with one and only one thing, counting up the overlapping points of lines. Let the caller
 // concern itself with which lines should be counted.
 func CountHotSpots(lines []Line) int {
-	vents := make(map[Point]int)
+	vents := make(grid.Sparse)
 
 	for _, line := range lines {
 		for _, point := range line.Points() {
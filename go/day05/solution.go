@@ -3,8 +3,18 @@ package day05
 import (
 	"io"
 	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  5,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
 type Solution struct {
 	lines []Line
 }
@@ -13,6 +23,10 @@ func (s *Solution) Name() string {
 	return "Hydrothermal Venture"
 }
 
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
 func (s *Solution) Load(r io.Reader) error {
 	lines, err := Parse(r)
 	if err != nil {
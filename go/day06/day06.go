@@ -1,6 +1,8 @@
 package day06
 
 import (
+	"errors"
+	"math/bits"
 	"strconv"
 	"strings"
 )
@@ -14,17 +16,27 @@ import (
 // at the next opportunity.
 type LanternFish [9]uint64
 
-func (l LanternFish) Pop() uint64 {
+// ErrOverflow is returned once the population has grown past what a uint64
+// can count. The school roughly doubles every week so, with a puzzle input's
+// few hundred fish, that happens at somewhere around 440 days. Rather than
+// quietly wrapping around to an answer that's nonsense we'd sooner just say
+// so.
+var ErrOverflow = errors.New("lanternfish population overflows a uint64")
+
+func (l LanternFish) Pop() (uint64, error) {
 	var sum uint64
 
 	for _, fish := range l {
-		sum += fish
+		var carry uint64
+		if sum, carry = bits.Add64(sum, fish, 0); carry != 0 {
+			return 0, ErrOverflow
+		}
 	}
 
-	return sum
+	return sum, nil
 }
 
-func SimulatePopulation(population LanternFish, days int) LanternFish {
+func SimulatePopulation(population LanternFish, days int) (LanternFish, error) {
 	for day := 0; day < days; day++ {
 		var next LanternFish
 
@@ -41,12 +53,15 @@ func SimulatePopulation(population LanternFish, days int) LanternFish {
 		// The same size population that just "birthed" new lantern
 		// fish with a timer value of 8 will aslo have their individual
 		// timers' reset to 6.
-		next[6] += next[8]
+		var carry uint64
+		if next[6], carry = bits.Add64(next[6], next[8], 0); carry != 0 {
+			return population, ErrOverflow
+		}
 
 		population = next
 	}
 
-	return population
+	return population, nil
 }
 
 func Parse(s string) (LanternFish, error) {
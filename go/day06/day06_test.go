@@ -10,6 +10,34 @@ import (
 func Test_LanternFish(t *testing.T) {
 	fish, err := Parse("3,4,3,1,2")
 	require.NoError(t, err)
-	assert.Equal(t, uint64(5934), SimulatePopulation(fish, 80).Pop())
-	assert.Equal(t, uint64(26984457539), SimulatePopulation(fish, 256).Pop())
+
+	for days, want := range map[int]uint64{80: 5934, 256: 26984457539} {
+		after, err := SimulatePopulation(fish, days)
+		require.NoError(t, err)
+
+		pop, err := after.Pop()
+		require.NoError(t, err)
+		assert.Equal(t, want, pop)
+	}
+}
+
+func Test_Overflow(t *testing.T) {
+	fish, err := Parse("3,4,3,1,2")
+	require.NoError(t, err)
+
+	// A uint64 can count the example's five fish through the 489th
+	// day but not the 490th, when the sum in Pop is what overflows.
+	after, err := SimulatePopulation(fish, 489)
+	require.NoError(t, err)
+	_, err = after.Pop()
+	require.NoError(t, err)
+
+	after, err = SimulatePopulation(fish, 490)
+	require.NoError(t, err)
+	_, err = after.Pop()
+	assert.ErrorIs(t, err, ErrOverflow)
+
+	// Left long enough a single timer's worth of fish overflows too.
+	_, err = SimulatePopulation(fish, 2000)
+	assert.ErrorIs(t, err, ErrOverflow)
 }
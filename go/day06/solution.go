@@ -1,26 +1,69 @@
 package day06
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"strings"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  6,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
+var (
+	_DAYS1 = registry.Param{
+		Name:    "days1",
+		Usage:   "days to simulate the lanternfish for in part one",
+		Default: 80,
+		Check:   registry.AtLeast(0),
+	}
+
+	_DAYS2 = registry.Param{
+		Name:    "days2",
+		Usage:   "days to simulate the lanternfish for in part two",
+		Default: 256,
+		Check:   registry.AtLeast(0),
+	}
 )
 
 type Solution struct {
 	fish LanternFish
+	args registry.Args
 }
 
 func (s *Solution) Name() string {
 	return "Lanternfish"
 }
 
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
+func (s *Solution) Params() []registry.Param {
+	return []registry.Param{_DAYS1, _DAYS2}
+}
+
+func (s *Solution) SetArgs(args registry.Args) {
+	s.args = args
+}
+
 func (s *Solution) Load(r io.Reader) error {
 	b, err := ioutil.ReadAll(r)
 	if err != nil {
 		return err
 	}
 
-	fish, err := Parse(string(b))
+	// Puzzle inputs saved straight from the browser end with a
+	// trailing newline that the embedded input doesn't have.
+	fish, err := Parse(strings.TrimSpace(string(b)))
 	if err != nil {
 		return err
 	}
@@ -30,10 +73,41 @@ func (s *Solution) Load(r io.Reader) error {
 	return nil
 }
 
+func (s *Solution) population(days int) (string, error) {
+	if days < 0 {
+		return "", errors.New("days can't be negative")
+	}
+
+	fish, err := SimulatePopulation(s.fish, days)
+	if err != nil {
+		return "", err
+	}
+
+	pop, err := fish.Pop()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%v", pop), nil
+}
+
 func (s *Solution) PartOne() (string, error) {
-	return fmt.Sprintf("%v", SimulatePopulation(s.fish, 80).Pop()), nil
+	return s.population(s.args.Int(_DAYS1))
 }
 
 func (s *Solution) PartTwo() (string, error) {
-	return fmt.Sprintf("%v", SimulatePopulation(s.fish, 256).Pop()), nil
+	return s.population(s.args.Int(_DAYS2))
+}
+
+func (s *Solution) Operations() []registry.Operation {
+	return []registry.Operation{
+		{
+			Name:  "SimulatePopulation",
+			Args:  []string{"days"},
+			Usage: "count the lanternfish after the given number of days",
+			Call: func(args []int) (string, error) {
+				return s.population(args[0])
+			},
+		},
+	}
 }
@@ -1,6 +1,7 @@
 package day07
 
 import (
+	"context"
 	"math"
 	"sort"
 	"strconv"
@@ -23,19 +24,36 @@ func Max(i, j int) int {
 // the step size is arbitrarily halved each time, leading ultimately
 // to a final 1-lookahead greedy, hill-climbing algorithm.
 func SillyLineSearch(cost CostFunction, guess, step int) int {
-	reference := cost(guess)
-	lower := cost(guess - step)
-	higher := cost(guess + step)
-
-	switch {
-	case step == 1 && (reference < lower && reference < higher):
-		return int(reference)
-	case lower < reference:
-		return SillyLineSearch(cost, guess-step, Max(1, step/2))
-	case higher < reference:
-		return SillyLineSearch(cost, guess+step, Max(1, step/2))
-	default:
-		return SillyLineSearch(cost, guess, Max(1, step/2))
+	min, _ := SillyLineSearchContext(context.Background(), cost, guess, step)
+	return min
+}
+
+// Nothing about a silly line search guarantees that it ever stops. A cost
+// function with a plateau or a second valley will happily keep it bouncing
+// around forever, so this version checks in with ctx before every step and
+// gives up as soon as it has been cancelled. It loops rather than recursing
+// on each step, since a search that runs long enough would otherwise blow
+// the stack long before anyone got around to cancelling it.
+func SillyLineSearchContext(ctx context.Context, cost CostFunction, guess, step int) (int, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		reference := cost(guess)
+		lower := cost(guess - step)
+		higher := cost(guess + step)
+
+		switch {
+		case step == 1 && (reference < lower && reference < higher):
+			return int(reference), nil
+		case lower < reference:
+			guess -= step
+		case higher < reference:
+			guess += step
+		}
+
+		step = Max(1, step/2)
 	}
 }
 
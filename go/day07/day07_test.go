@@ -1,7 +1,9 @@
 package day07
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -17,3 +19,17 @@ func Test_Optimizer(t *testing.T) {
 	assert.Equal(t, 37, int(SillyLineSearch(L1(nums), mid, 256)))
 	assert.Equal(t, 168, int(SillyLineSearch(L2(nums), mid, 256)))
 }
+
+func Test_OptimizerGivesUp(t *testing.T) {
+	// A flat cost never has a strict minimum for the search to settle
+	// on, so without a deadline it would keep going forever. The deadline
+	// is long enough that a search recursing on every step would run out
+	// of stack before reaching it.
+	flat := func(int) float64 { return 1 }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := SillyLineSearchContext(ctx, flat, 0, 256)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
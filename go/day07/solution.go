@@ -1,11 +1,22 @@
 package day07
 
 import (
+	"context"
 	"io"
 	"io/ioutil"
 	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  7,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
 type Solution struct {
 	nums []int
 }
@@ -15,6 +26,10 @@ func (s *Solution) Name() string {
 }
 
 func (s *Solution) Load(r io.Reader) error {
+	return s.LoadContext(context.Background(), r)
+}
+
+func (s *Solution) LoadContext(ctx context.Context, r io.Reader) error {
 	b, err := ioutil.ReadAll(r)
 	if err != nil {
 		return err
@@ -31,13 +46,31 @@ func (s *Solution) Load(r io.Reader) error {
 }
 
 func (s *Solution) PartOne() (string, error) {
+	return s.PartOneContext(context.Background())
+}
+
+func (s *Solution) PartOneContext(ctx context.Context) (string, error) {
 	mid := MidPoint(s.nums)
 
-	return strconv.Itoa(int(SillyLineSearch(L1(s.nums), mid, 256))), nil
+	fuel, err := SillyLineSearchContext(ctx, L1(s.nums), mid, 256)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(fuel), nil
 }
 
 func (s *Solution) PartTwo() (string, error) {
+	return s.PartTwoContext(context.Background())
+}
+
+func (s *Solution) PartTwoContext(ctx context.Context) (string, error) {
 	mid := MidPoint(s.nums)
 
-	return strconv.Itoa(int(SillyLineSearch(L2(s.nums), mid, 256))), nil
+	fuel, err := SillyLineSearchContext(ctx, L2(s.nums), mid, 256)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(fuel), nil
 }
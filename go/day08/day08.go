@@ -2,6 +2,7 @@ package day08
 
 import (
 	"bufio"
+	"context"
 	"io"
 	"math"
 	"strings"
@@ -262,10 +263,22 @@ func EasyDigitCount(signals []Signal) int {
 }
 
 func SignalOutputSum(signals []Signal) int {
+	sum, _ := SignalOutputSumContext(context.Background(), signals)
+	return sum
+}
+
+// Decoding a signal means trying every one of the 5040 wirings until one
+// fits, so a long enough list of signals takes a while. SignalOutputSumContext
+// checks ctx between signals and gives up once it's been cancelled.
+func SignalOutputSumContext(ctx context.Context, signals []Signal) (int, error) {
 	var sum int
 	for _, signal := range signals {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
 		sum += signal.Output()
 	}
 
-	return sum
+	return sum, nil
 }
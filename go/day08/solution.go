@@ -1,10 +1,21 @@
 package day08
 
 import (
+	"context"
 	"io"
 	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  8,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
 type Solution struct {
 	signals []Signal
 }
@@ -13,7 +24,15 @@ func (s *Solution) Name() string {
 	return "Seven Segment Search"
 }
 
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
 func (s *Solution) Load(r io.Reader) error {
+	return s.LoadContext(context.Background(), r)
+}
+
+func (s *Solution) LoadContext(ctx context.Context, r io.Reader) error {
 	signals, err := Parse(r)
 	if err != nil {
 		return err
@@ -25,9 +44,22 @@ func (s *Solution) Load(r io.Reader) error {
 }
 
 func (s *Solution) PartOne() (string, error) {
+	return s.PartOneContext(context.Background())
+}
+
+func (s *Solution) PartOneContext(ctx context.Context) (string, error) {
 	return strconv.Itoa(EasyDigitCount(s.signals)), nil
 }
 
 func (s *Solution) PartTwo() (string, error) {
-	return strconv.Itoa(SignalOutputSum(s.signals)), nil
+	return s.PartTwoContext(context.Background())
+}
+
+func (s *Solution) PartTwoContext(ctx context.Context) (string, error) {
+	sum, err := SignalOutputSumContext(ctx, s.signals)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(sum), nil
 }
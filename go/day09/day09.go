@@ -1,15 +1,14 @@
 package day09
 
 import (
-	"bufio"
 	"io"
 	"sort"
-	"strconv"
-	"strings"
+
+	"github.com/stntngo/advent-2021/go/grid"
 )
 
 type HeightNode struct {
-	Coordinate
+	grid.Point
 	Value int
 
 	Inflows []*HeightNode
@@ -18,29 +17,21 @@ type HeightNode struct {
 type NodeMap [][]*HeightNode
 
 func ConvertNodeMap(hm HeightMap) NodeMap {
-	nm := make(NodeMap, 0, len(hm))
-
-	for y, row := range hm {
-		nmr := make([]*HeightNode, 0, len(row))
-
-		for x, value := range row {
-			node := &HeightNode{
-				Coordinate: Coordinate{
-					X: x,
-					Y: y,
-				},
-				Value: value,
-			}
+	nm := make(NodeMap, hm.Height())
+	for y := range nm {
+		nm[y] = make([]*HeightNode, hm.Width())
+	}
 
-			nmr = append(nmr, node)
+	hm.Each(func(p grid.Point, value int) {
+		nm[p.Y][p.X] = &HeightNode{
+			Point: p,
+			Value: value,
 		}
-
-		nm = append(nm, nmr)
-	}
+	})
 
 	for _, row := range nm {
 		for _, node := range row {
-			for _, coords := range hm.Neighbors(node.Coordinate) {
+			for _, coords := range hm.Neighbors(node.Point, grid.Four) {
 				neighbor := nm[coords.Y][coords.X]
 
 				if neighbor.Value == 9 {
@@ -58,40 +49,16 @@ func ConvertNodeMap(hm HeightMap) NodeMap {
 	return nm
 }
 
-type Coordinate struct {
-	X, Y int
-}
-
-type HeightMap [][]int
-
-func (h HeightMap) Neighbors(coord Coordinate) []Coordinate {
-	var neighbors []Coordinate
-
-	switch coord.X {
-	case 0:
-		neighbors = append(neighbors, Coordinate{coord.X + 1, coord.Y})
-	case len(h[0]) - 1:
-		neighbors = append(neighbors, Coordinate{coord.X - 1, coord.Y})
-	default:
-		neighbors = append(neighbors, Coordinate{coord.X - 1, coord.Y}, Coordinate{coord.X + 1, coord.Y})
-	}
-
-	switch coord.Y {
-	case 0:
-		neighbors = append(neighbors, Coordinate{coord.X, coord.Y + 1})
-	case len(h) - 1:
-		neighbors = append(neighbors, Coordinate{coord.X, coord.Y - 1})
-	default:
-		neighbors = append(neighbors, Coordinate{coord.X, coord.Y - 1}, Coordinate{coord.X, coord.Y + 1})
-	}
-
-	return neighbors
+// HeightMap is the height of every cell of the cave floor. Smoke only flows
+// between cells that are directly next to each other, never diagonally.
+type HeightMap struct {
+	*grid.Dense
 }
 
-func (h HeightMap) IsLowPoint(coord Coordinate) bool {
-	value := h[coord.Y][coord.X]
-	for _, neighbor := range h.Neighbors(coord) {
-		if h[neighbor.Y][neighbor.X] <= value {
+func (h HeightMap) IsLowPoint(coord grid.Point) bool {
+	value := h.At(coord)
+	for _, neighbor := range h.Neighbors(coord, grid.Four) {
+		if h.At(neighbor) <= value {
 			return false
 		}
 
@@ -104,24 +71,22 @@ func (hm HeightMap) LowPoints() []*HeightNode {
 	nm := ConvertNodeMap(hm)
 
 	var out []*HeightNode
-	for y, row := range hm {
-		for x := range row {
-			if hm.IsLowPoint(Coordinate{x, y}) {
-				out = append(out, nm[y][x])
-			}
+	hm.Each(func(p grid.Point, _ int) {
+		if hm.IsLowPoint(p) {
+			out = append(out, nm[p.Y][p.X])
 		}
-	}
+	})
 
 	return out
 
 }
 
-func (hm HeightMap) Basins() []map[Coordinate]bool {
-	var out []map[Coordinate]bool
+func (hm HeightMap) Basins() []map[grid.Point]bool {
+	var out []map[grid.Point]bool
 	for _, node := range hm.LowPoints() {
-		basin := make(map[Coordinate]bool)
+		basin := make(map[grid.Point]bool)
 
-		basin[node.Coordinate] = true
+		basin[node.Point] = true
 
 		queue := make([]*HeightNode, len(node.Inflows))
 		copy(queue, node.Inflows)
@@ -129,7 +94,7 @@ func (hm HeightMap) Basins() []map[Coordinate]bool {
 		for len(queue) > 0 {
 			node, queue = queue[0], queue[1:]
 
-			basin[node.Coordinate] = true
+			basin[node.Point] = true
 
 			queue = append(queue, node.Inflows...)
 		}
@@ -165,29 +130,10 @@ func (hm HeightMap) BasinFactor() int {
 }
 
 func Parse(r io.Reader) (HeightMap, error) {
-	scanner := bufio.NewScanner(r)
-
-	var h HeightMap
-
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		row := make([]int, 0, len(line))
-		for _, n := range strings.Split(line, "") {
-			height, err := strconv.Atoi(n)
-			if err != nil {
-				return nil, err
-			}
-
-			row = append(row, height)
-		}
-
-		h = append(h, row)
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, err
+	d, err := grid.ParseDigits(r)
+	if err != nil {
+		return HeightMap{}, err
 	}
 
-	return h, nil
+	return HeightMap{d}, nil
 }
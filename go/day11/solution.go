@@ -1,18 +1,55 @@
 package day11
 
 import (
+	"errors"
 	"io"
 	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
+	"github.com/stntngo/advent-2021/go/sim"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  11,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
+var _STEPS = registry.Param{
+	Name:    "steps",
+	Usage:   "steps to count the flashes over in part one",
+	Default: 100,
+	Check:   registry.AtLeast(0),
+}
+
+// _LIMIT is how many steps to wait for the octopuses to all flash at once
+// before deciding they never will.
+const _LIMIT = 100000
+
 type Solution struct {
 	cavern Cavern
+	args   registry.Args
 }
 
 func (s *Solution) Name() string {
 	return "Dumbo Octopus"
 }
 
+// ConcurrentParts holds since both parts step their own Copy of the cavern.
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
+func (s *Solution) Params() []registry.Param {
+	return []registry.Param{_STEPS}
+}
+
+func (s *Solution) SetArgs(args registry.Args) {
+	s.args = args
+}
+
 func (s *Solution) Load(r io.Reader) error {
 	cavern, err := ParseCavern(r)
 	if err != nil {
@@ -26,24 +63,25 @@ func (s *Solution) Load(r io.Reader) error {
 }
 
 func (s *Solution) PartOne() (string, error) {
-	var total int
+	steps := s.args.Int(_STEPS)
+	if steps < 0 {
+		return "", errors.New("steps can't be negative")
+	}
 
 	cavern := s.cavern.Copy()
-	for i := 0; i < 100; i++ {
-		total += cavern.Step()
-	}
 
-	return strconv.Itoa(total), nil
+	return strconv.Itoa(sim.Run(&cavern, steps)), nil
 }
 
 func (s *Solution) PartTwo() (string, error) {
 	cavern := s.cavern.Copy()
 
-	var step int
-	for {
-		step++
-		if cavern.Step() == (_SIZE * _SIZE) {
-			return strconv.Itoa(step), nil
-		}
+	step, ok := sim.Until(&cavern, _LIMIT, func(flashes int) bool {
+		return flashes == cavern.Len()
+	})
+	if !ok {
+		return "", errors.New("the octopuses never all flash at once")
 	}
+
+	return strconv.Itoa(step), nil
 }
@@ -2,6 +2,7 @@ package day12
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -102,14 +103,28 @@ type Cave struct {
 	paths       map[string]int
 }
 
-func (c *Cave) Paths(max int, visited ...*Cave) (paths int) {
+func (c *Cave) Paths(max int, visited ...*Cave) int {
+	paths, _ := c.PathsContext(context.Background(), max, visited...)
+	return paths
+}
+
+// PathsContext counts the paths from c to the end of the cave system just
+// like Paths, but stops exploring as soon as ctx is cancelled. Counts from a
+// search that was cut short are never memoized since they'd be too low.
+func (c *Cave) PathsContext(ctx context.Context, max int, visited ...*Cave) (paths int, err error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
 	hash := VisitHash(visited)
 	if count, ok := c.paths[hash]; ok {
-		return count
+		return count, nil
 	}
 
 	defer func() {
-		c.paths[hash] = paths
+		if err == nil {
+			c.paths[hash] = paths
+		}
 	}()
 
 	seen := make(map[string]int)
@@ -122,15 +137,15 @@ func (c *Cave) Paths(max int, visited ...*Cave) (paths int) {
 	switch c.Kind {
 	case Start:
 		if seen[c.Name] == 1 {
-			return 0
+			return 0, nil
 		}
 	case End:
 		if seen[c.Name] == 1 {
-			return 0
+			return 0, nil
 		}
 	case Small:
 		if seen[c.Name] == max {
-			return 0
+			return 0, nil
 		}
 	}
 
@@ -142,20 +157,25 @@ func (c *Cave) Paths(max int, visited ...*Cave) (paths int) {
 	}
 
 	if atmax > 1 {
-		return 0
+		return 0, nil
 	}
 
 	if c.Kind == End {
-		return 1
+		return 1, nil
 	}
 
 	visited = append([]*Cave{c}, visited...)
 
 	for _, cxn := range c.Connections {
-		paths += cxn.Paths(max, visited...)
+		n, err := cxn.PathsContext(ctx, max, visited...)
+		if err != nil {
+			return 0, err
+		}
+
+		paths += n
 	}
 
-	return paths
+	return paths, nil
 }
 
 func NewCave(id string) *Cave {
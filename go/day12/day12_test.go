@@ -93,3 +93,15 @@ func Test_EvenLargerPart2(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 3509, caves.Start().Paths(2))
 }
+
+func Test_PathsOperation(t *testing.T) {
+	var s Solution
+	require.NoError(t, s.Load(strings.NewReader(example)))
+
+	paths := s.Operations()[0]
+	for _, max := range []int{2, 1, 2} {
+		count, err := paths.Call([]int{max})
+		require.NoError(t, err)
+		assert.Equal(t, map[int]string{1: "10", 2: "36"}[max], count)
+	}
+}
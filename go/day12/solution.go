@@ -2,34 +2,84 @@ package day12
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
+	"io/ioutil"
 	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  12,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
+var (
+	_VISITS1 = registry.Param{
+		Name:    "visits1",
+		Usage:   "most visits to a single small cave in part one",
+		Default: 1,
+		Check:   registry.AtLeast(1),
+	}
+
+	_VISITS2 = registry.Param{
+		Name:    "visits2",
+		Usage:   "most visits to a single small cave in part two",
+		Default: 2,
+		Check:   registry.AtLeast(1),
+	}
 )
 
 type Solution struct {
 	p1, p2 CaveSystem
 	buffer []byte
+	args   registry.Args
 }
 
 func (s *Solution) Name() string {
 	return "Passage Pathing"
 }
 
+// ConcurrentParts holds since each part searches its own CaveSystem, which
+// is why Load parses the caves twice: the memoized paths aren't shared.
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
+func (s *Solution) Params() []registry.Param {
+	return []registry.Param{_VISITS1, _VISITS2}
+}
+
+func (s *Solution) SetArgs(args registry.Args) {
+	s.args = args
+}
+
 func (s *Solution) Load(r io.Reader) error {
-	var b1, b2 bytes.Buffer
+	return s.LoadContext(context.Background(), r)
+}
 
-	w := io.MultiWriter(&b1, &b2)
-	if _, err := io.Copy(w, r); err != nil {
+func (s *Solution) LoadContext(ctx context.Context, r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
 		return err
 	}
 
-	p1, err := ParseCaves(&b1)
+	// Hang on to the raw input so that the Paths operation can
+	// parse a fresh CaveSystem of its own every time it's called.
+	s.buffer = b
+
+	p1, err := ParseCaves(bytes.NewReader(b))
 	if err != nil {
 		return err
 	}
 	s.p1 = p1
 
-	p2, err := ParseCaves(&b2)
+	p2, err := ParseCaves(bytes.NewReader(b))
 	if err != nil {
 		return err
 	}
@@ -39,9 +89,63 @@ func (s *Solution) Load(r io.Reader) error {
 }
 
 func (s *Solution) PartOne() (string, error) {
-	return strconv.Itoa(s.p1.Start().Paths(1)), nil
+	return s.PartOneContext(context.Background())
+}
+
+func (s *Solution) PartOneContext(ctx context.Context) (string, error) {
+	visits := s.args.Int(_VISITS1)
+	if visits < 1 {
+		return "", errors.New("visits must be at least 1")
+	}
+
+	paths, err := s.p1.Start().PathsContext(ctx, visits)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(paths), nil
 }
 
 func (s *Solution) PartTwo() (string, error) {
-	return strconv.Itoa(s.p2.Start().Paths(2)), nil
+	return s.PartTwoContext(context.Background())
+}
+
+func (s *Solution) PartTwoContext(ctx context.Context) (string, error) {
+	visits := s.args.Int(_VISITS2)
+	if visits < 1 {
+		return "", errors.New("visits must be at least 1")
+	}
+
+	paths, err := s.p2.Start().PathsContext(ctx, visits)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(paths), nil
+}
+
+func (s *Solution) Operations() []registry.Operation {
+	return []registry.Operation{
+		{
+			Name:  "Paths",
+			Args:  []string{"max"},
+			Usage: "count the paths that visit a single small cave at most max times",
+			Call: func(args []int) (string, error) {
+				if args[0] < 1 {
+					return "", errors.New("max must be at least 1")
+				}
+
+				// The memoized path counts on each Cave don't
+				// take max into account, so a CaveSystem that's
+				// already been searched with one max can't be
+				// reused for another.
+				caves, err := ParseCaves(bytes.NewReader(s.buffer))
+				if err != nil {
+					return "", err
+				}
+
+				return strconv.Itoa(caves.Start().Paths(args[0])), nil
+			},
+		},
+	}
 }
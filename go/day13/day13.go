@@ -7,6 +7,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/stntngo/advent-2021/go/grid"
 )
 
 func Transpose(lines [][]string) [][]string {
@@ -36,61 +38,45 @@ var (
 	instruction = regexp.MustCompile("([xy])=(\\d+)")
 )
 
-type Point struct {
-	x, y int
-}
-
-func (p Point) Reflect(axis Direction, value int) Point {
+func Reflect(p grid.Point, axis Direction, value int) grid.Point {
 	switch axis {
 	case Vertical:
-		return Point{
-			x: value - Abs(value-p.x),
-			y: p.y,
+		return grid.Point{
+			X: value - Abs(value-p.X),
+			Y: p.Y,
 		}
 	case Horizontal:
-		return Point{
-			x: p.x,
-			y: value - Abs(value-p.y),
+		return grid.Point{
+			X: p.X,
+			Y: value - Abs(value-p.Y),
 		}
 	}
 
 	panic("unreachable")
 }
 
-type Grid map[Point]int
-
-func (g Grid) Max() (int, int) {
-	var x, y int
-
-	for p := range g {
-		if p.x > x {
-			x = p.x
-		}
-
-		if p.y > y {
-			y = p.y
-		}
-	}
-
-	return x, y
-}
+// Grid counts the dots on each point of the paper.
+type Grid grid.Sparse
 
+// Lines draws the paper from its top left corner, whether or not there are
+// any dots up there, to the furthest dot along each axis.
 func (g Grid) Lines() [][]string {
-	mx, my := g.Max()
-
-	lines := make([][]string, my+1)
-	for y := 0; y <= my; y++ {
-		line := make([]string, mx+1)
-		for x := 0; x <= mx; x++ {
-			if _, ok := g[Point{x, y}]; ok {
-				line[x] = "#"
-			} else {
-				line[x] = " "
-			}
+	var bounds grid.Bounds
+	if b, ok := grid.Sparse(g).Bounds(); ok {
+		bounds.Max = b.Max
+	}
 
+	drawn := grid.Sparse(g).Render(bounds, func(_ int, ok bool) byte {
+		if ok {
+			return '#'
 		}
 
-		lines[y] = line
+		return ' '
+	})
+
+	var lines [][]string
+	for _, line := range strings.Split(strings.TrimSuffix(drawn, "\n"), "\n") {
+		lines = append(lines, strings.Split(line, ""))
 	}
 
 	return lines
@@ -139,30 +125,30 @@ func ParseInstruction(raw string) (Instruction, error) {
 	return inst, nil
 }
 
-func ParsePoint(s string) (Point, error) {
+func ParsePoint(s string) (grid.Point, error) {
 	parts := strings.Split(s, ",")
 	if len(parts) != 2 {
-		return Point{}, errors.New("unexepcted number of point parts")
+		return grid.Point{}, errors.New("unexepcted number of point parts")
 	}
 
 	x, err := strconv.Atoi(parts[0])
 	if err != nil {
-		return Point{}, err
+		return grid.Point{}, err
 	}
 
 	y, err := strconv.Atoi(parts[1])
 	if err != nil {
-		return Point{}, err
+		return grid.Point{}, err
 	}
 
-	return Point{x, y}, nil
+	return grid.Point{X: x, Y: y}, nil
 }
 
 func ParsePoints(r io.Reader) (Grid, []Instruction, error) {
 	scanner := bufio.NewScanner(r)
 
 	var instructions []Instruction
-	grid := make(map[Point]int)
+	dots := make(Grid)
 
 	parser := func(s string) error {
 		point, err := ParsePoint(s)
@@ -170,7 +156,7 @@ func ParsePoints(r io.Reader) (Grid, []Instruction, error) {
 			return err
 		}
 
-		grid[point]++
+		dots[point]++
 
 		return nil
 	}
@@ -201,30 +187,30 @@ func ParsePoints(r io.Reader) (Grid, []Instruction, error) {
 		return nil, nil, err
 	}
 
-	return grid, instructions, nil
+	return dots, instructions, nil
 }
 
-func Fold(grid Grid, instructions []Instruction) Grid {
+func Fold(dots Grid, instructions []Instruction) Grid {
 	for _, instruction := range instructions {
-		next := make(map[Point]int)
-		for point := range grid {
-			next[point.Reflect(instruction.Direction, instruction.Value)]++
+		next := make(Grid)
+		for point := range dots {
+			next[Reflect(point, instruction.Direction, instruction.Value)]++
 		}
 
-		grid = next
+		dots = next
 	}
 
-	return grid
+	return dots
 }
 
-func Translate(grid Grid) (string, error) {
-	lines := grid.Lines()
+func Translate(dots Grid) (string, error) {
+	lines := dots.Lines()
 
 	if len(lines) != 6 {
 		return "", errors.New("unable to translate grid")
 	}
 
-	transposed := Transpose(grid.Lines())
+	transposed := Transpose(lines)
 
 	var output string
 	for len(transposed) > 0 {
@@ -2,6 +2,7 @@ package day13
 
 import (
 	"fmt"
+	"os"
 	"strings"
 )
 
@@ -13,9 +14,11 @@ var mapping = map[int]string{
 	6916246:  "C",
 }
 
+// printchar writes to stderr rather than stdout so that an unrecognized
+// letter can't corrupt the runner's machine readable output formats.
 func printchar(character [][]string) {
 	for _, line := range character {
-		fmt.Println(strings.Join(line, ""))
+		fmt.Fprintln(os.Stderr, strings.Join(line, ""))
 	}
 }
 
@@ -3,8 +3,18 @@ package day13
 import (
 	"io"
 	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  13,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
 type Solution struct {
 	grid         Grid
 	instructions []Instruction
@@ -14,6 +24,10 @@ func (s *Solution) Name() string {
 	return "Transparent Origami"
 }
 
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
 func (s *Solution) Load(r io.Reader) error {
 	grid, instructions, err := ParsePoints(r)
 	if err != nil {
@@ -4,9 +4,26 @@ import (
 	"bufio"
 	"errors"
 	"io"
+	"math/bits"
 	"strings"
 )
 
+// ErrOverflow is returned once a count of pairs or elements no longer fits
+// in a uint64. The polymer roughly doubles in length every round so, from
+// a puzzle input's template, that's somewhere past the 60th round.
+var ErrOverflow = errors.New("polymer count overflows a uint64")
+
+// add is a + b, unless that would wrap around, which we'd rather hear about
+// than carry on with.
+func add(a, b uint64) (uint64, error) {
+	sum, carry := bits.Add64(a, b, 0)
+	if carry != 0 {
+		return 0, ErrOverflow
+	}
+
+	return sum, nil
+}
+
 type Insertion struct {
 	input  string
 	output []string
@@ -55,7 +72,7 @@ func ParsePolymers(r io.Reader) (string, []Insertion, error) {
 	return template, rules, nil
 }
 
-func ExtendPolymer(template string, rules []Insertion, rounds int) map[string]uint64 {
+func ExtendPolymer(template string, rules []Insertion, rounds int) (map[string]uint64, error) {
 	counter := make(map[string]uint64)
 
 	for _, pair := range Window(template, 2) {
@@ -69,7 +86,12 @@ func ExtendPolymer(template string, rules []Insertion, rounds int) map[string]ui
 			for _, rule := range rules {
 				if rule.input == pair {
 					for _, translation := range rule.output {
-						next[translation] += count
+						sum, err := add(next[translation], count)
+						if err != nil {
+							return nil, err
+						}
+
+						next[translation] = sum
 					}
 
 					break
@@ -80,7 +102,7 @@ func ExtendPolymer(template string, rules []Insertion, rounds int) map[string]ui
 		counter = next
 	}
 
-	return counter
+	return counter, nil
 }
 
 func Window(template string, size int) []string {
@@ -93,16 +115,28 @@ func Window(template string, size int) []string {
 
 }
 
-func PolymerScore(initial string, polymer map[string]uint64) uint64 {
+func PolymerScore(initial string, polymer map[string]uint64) (uint64, error) {
 	counter := make(map[string]uint64)
 
 	for pair, count := range polymer {
 		terms := strings.Split(pair, "")
-		counter[terms[1]] += count
+
+		sum, err := add(counter[terms[1]], count)
+		if err != nil {
+			return 0, err
+		}
+
+		counter[terms[1]] = sum
 	}
 
 	first := strings.Split(initial, "")[0]
-	counter[first]++
+
+	sum, err := add(counter[first], 1)
+	if err != nil {
+		return 0, err
+	}
+
+	counter[first] = sum
 
 	var max, min uint64
 	for _, value := range counter {
@@ -115,5 +149,5 @@ func PolymerScore(initial string, polymer map[string]uint64) uint64 {
 		}
 	}
 
-	return max - min
+	return max - min, nil
 }
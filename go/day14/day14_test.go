@@ -0,0 +1,61 @@
+package day14
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCase = `NNCB
+
+CH -> B
+HH -> N
+CB -> H
+NH -> C
+HB -> C
+HC -> B
+HN -> C
+NN -> C
+BH -> H
+NC -> B
+NB -> B
+BN -> B
+BB -> N
+BC -> B
+CC -> N
+CN -> C`
+
+func score(t *testing.T, rounds int) (uint64, error) {
+	template, rules, err := ParsePolymers(strings.NewReader(testCase))
+	require.NoError(t, err)
+
+	extended, err := ExtendPolymer(template, rules, rounds)
+	if err != nil {
+		return 0, err
+	}
+
+	return PolymerScore(template, extended)
+}
+
+func Test_PolymerScore(t *testing.T) {
+	for rounds, want := range map[int]uint64{10: 1588, 40: 2188189693529} {
+		got, err := score(t, rounds)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
+func Test_Overflow(t *testing.T) {
+	// The example polymer can be counted through 63 rounds of insertions
+	// but the 64th is more than a uint64 holds.
+	_, err := score(t, 63)
+	require.NoError(t, err)
+
+	_, err = score(t, 64)
+	assert.ErrorIs(t, err, ErrOverflow)
+
+	_, err = score(t, 100)
+	assert.ErrorIs(t, err, ErrOverflow)
+}
@@ -1,19 +1,59 @@
 package day14
 
 import (
+	"errors"
 	"fmt"
 	"io"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  14,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
+var (
+	_ROUNDS1 = registry.Param{
+		Name:    "rounds1",
+		Usage:   "rounds of pair insertion in part one",
+		Default: 10,
+		Check:   registry.AtLeast(0),
+	}
+
+	_ROUNDS2 = registry.Param{
+		Name:    "rounds2",
+		Usage:   "rounds of pair insertion in part two",
+		Default: 40,
+		Check:   registry.AtLeast(0),
+	}
 )
 
 type Solution struct {
 	template string
 	rules    []Insertion
+	args     registry.Args
 }
 
 func (s *Solution) Name() string {
 	return "Extended Polymerization"
 }
 
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
+func (s *Solution) Params() []registry.Param {
+	return []registry.Param{_ROUNDS1, _ROUNDS2}
+}
+
+func (s *Solution) SetArgs(args registry.Args) {
+	s.args = args
+}
+
 func (s *Solution) Load(r io.Reader) error {
 	template, rules, err := ParsePolymers(r)
 	s.template = template
@@ -22,14 +62,41 @@ func (s *Solution) Load(r io.Reader) error {
 	return err
 }
 
-func (s *Solution) PartOne() (string, error) {
-	extended := ExtendPolymer(s.template, s.rules, 10)
+func (s *Solution) score(rounds int) (string, error) {
+	if rounds < 0 {
+		return "", errors.New("rounds can't be negative")
+	}
+
+	extended, err := ExtendPolymer(s.template, s.rules, rounds)
+	if err != nil {
+		return "", err
+	}
+
+	score, err := PolymerScore(s.template, extended)
+	if err != nil {
+		return "", err
+	}
 
-	return fmt.Sprintf("%v", PolymerScore(s.template, extended)), nil
+	return fmt.Sprintf("%v", score), nil
+}
+
+func (s *Solution) PartOne() (string, error) {
+	return s.score(s.args.Int(_ROUNDS1))
 }
 
 func (s *Solution) PartTwo() (string, error) {
-	extended := ExtendPolymer(s.template, s.rules, 40)
+	return s.score(s.args.Int(_ROUNDS2))
+}
 
-	return fmt.Sprintf("%v", PolymerScore(s.template, extended)), nil
+func (s *Solution) Operations() []registry.Operation {
+	return []registry.Operation{
+		{
+			Name:  "ExtendPolymer",
+			Args:  []string{"rounds"},
+			Usage: "score the polymer after the given number of rounds of insertions",
+			Call: func(args []int) (string, error) {
+				return s.score(args[0])
+			},
+		},
+	}
 }
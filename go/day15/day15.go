@@ -1,10 +1,10 @@
 package day15
 
 import (
-	"bufio"
+	"errors"
 	"io"
-	"strconv"
-	"strings"
+
+	"github.com/stntngo/advent-2021/go/grid"
 )
 
 type edge struct {
@@ -24,8 +24,9 @@ func (e edge) Weight() int {
 }
 
 type chiton struct {
-	id, risk, x, y int
-	connections    []*chiton
+	grid.Point
+	id, risk    int
+	connections []*chiton
 }
 
 func (c *chiton) ID() ID {
@@ -45,95 +46,87 @@ func (c *chiton) Edges() []Edge {
 	return edges
 }
 
-func Risk(nodes map[int]*chiton, path []ID) int {
+// Cave is the risk level of every cell of the cave along with the chiton
+// in each of them, which are the nodes of the graph the search walks.
+type Cave struct {
+	risks *grid.Dense
+
+	// chitons holds the chiton in each cell row by row, which makes the
+	// ID of a chiton its index.
+	chitons []*chiton
+}
+
+// at is the chiton in the cell at p.
+func (c *Cave) at(p grid.Point) *chiton {
+	return c.chitons[p.Y*c.risks.Width()+p.X]
+}
+
+// start is the chiton in the top left, where the path begins.
+func (c *Cave) start() *chiton {
+	return c.at(grid.Point{})
+}
+
+// end is the chiton in the bottom right, where the path ends.
+func (c *Cave) end() *chiton {
+	return c.at(c.risks.Bounds().Max)
+}
+
+// Risk adds up the risk of every chiton along path.
+func (c *Cave) Risk(path []ID) int {
 	var risk int
 	for _, id := range path {
-		risk += nodes[int(id)].risk
+		risk += c.chitons[id].risk
 	}
 
 	return risk
 }
 
-func ParseNodes(r io.Reader, reps int) (map[int]*chiton, error) {
-	scanner := bufio.NewScanner(r)
-
-	var lines []string
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
+// ParseCave reads the risk levels of the cave and builds the graph of the
+// full cave from them, which is the cave repeated reps times across and down
+// with the risk going up by one, and wrapping around from 9 back to 1, for
+// each repetition away from the top left.
+func ParseCave(r io.Reader, reps int) (*Cave, error) {
+	if reps < 1 {
+		return nil, errors.New("reps must be at least 1")
 	}
 
-	var rows [][]*chiton
-	var id int
+	tile, err := grid.ParseDigits(r)
+	if err != nil {
+		return nil, err
+	}
 
-	var yi int
+	risks := grid.NewDense(tile.Width()*reps, tile.Height()*reps)
 	for y := 0; y < reps; y++ {
-		for _, line := range lines {
-			var row []*chiton
-			var xi int
-			for x := 0; x < reps; x++ {
-				for _, num := range strings.Split(line, "") {
-					risk, err := strconv.Atoi(num)
-					if err != nil {
-						return nil, err
-					}
-
-					risk = (risk + x + y)
-
-					if risk >= 10 {
-						risk = risk%10 + 1
-					}
-
-					row = append(
-						row,
-						&chiton{
-							id:          id,
-							risk:        risk,
-							x:           xi,
-							y:           yi,
-							connections: make([]*chiton, 0),
-						},
-					)
-
-					id++
-					xi++
-				}
-			}
-
-			rows = append(rows, row)
-			yi++
+		for x := 0; x < reps; x++ {
+			tile.Each(func(p grid.Point, risk int) {
+				// Risk runs from 1 to 9, so it wraps every 9 rather
+				// than every 10, however many times it goes round.
+				risk = (risk+x+y-1)%9 + 1
+
+				risks.Set(grid.Point{X: x*tile.Width() + p.X, Y: y*tile.Height() + p.Y}, risk)
+			})
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
+	cave := &Cave{
+		risks:   risks,
+		chitons: make([]*chiton, 0, risks.Len()),
 	}
 
-	out := make(map[int]*chiton)
-	for y, row := range rows {
-		for x, node := range row {
-			for _, yadj := range []int{-1, 1} {
-				ny := y + yadj
-
-				if ny < 0 || ny >= len(rows) {
-					continue
-				}
-
-				node.connections = append(node.connections, rows[ny][x])
-			}
-
-			for _, xadj := range []int{-1, 1} {
-				nx := x + xadj
-
-				if nx < 0 || nx >= len(row) {
-					continue
-				}
-
-				node.connections = append(node.connections, rows[y][nx])
-			}
-
-			out[node.id] = node
+	risks.Each(func(p grid.Point, risk int) {
+		cave.chitons = append(cave.chitons, &chiton{
+			Point:       p,
+			id:          len(cave.chitons),
+			risk:        risk,
+			connections: make([]*chiton, 0, len(grid.Four)),
+		})
+	})
+
+	for _, c := range cave.chitons {
+		for _, neighbor := range risks.Neighbors(c.Point, grid.Four) {
+			c.connections = append(c.connections, cave.at(neighbor))
 		}
 	}
 
-	return out, nil
+	return cave, nil
 }
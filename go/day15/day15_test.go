@@ -23,57 +23,75 @@ var testCase = `1163751742
 func Test_OneFold(t *testing.T) {
 	r := strings.NewReader(testCase)
 
-	nodes, err := ParseNodes(r, 1)
+	cave, err := ParseCave(r, 1)
 	require.NoError(t, err)
-	source := nodes[0]
-	end := nodes[len(nodes)-1]
+	source := cave.start()
+	end := cave.end()
 	path, err := FindPath(
 		source,
 		end,
 		func(node Node) int {
 			n := node.(*chiton)
 
-			return (end.x - n.x) + (end.y - n.y)
+			return (end.X - n.X) + (end.Y - n.Y)
 		},
 	)
 
 	require.NoError(t, err)
 
-	assert.Equal(t, 40, Risk(nodes, path))
+	assert.Equal(t, 40, cave.Risk(path))
 
 }
 
 func Test_FiveFold(t *testing.T) {
 	r := strings.NewReader(testCase)
 
-	nodes, err := ParseNodes(r, 5)
+	cave, err := ParseCave(r, 5)
 	require.NoError(t, err)
-	source := nodes[0]
-	end := nodes[len(nodes)-1]
+	source := cave.start()
+	end := cave.end()
 	path, err := FindPath(
 		source,
 		end,
 		func(node Node) int {
 			n := node.(*chiton)
 
-			return (end.x - n.x) + (end.y - n.y)
+			return (end.X - n.X) + (end.Y - n.Y)
 		},
 	)
 
 	require.NoError(t, err)
 
-	assert.Equal(t, 315, Risk(nodes, path))
+	assert.Equal(t, 315, cave.Risk(path))
 
 }
 
+func Test_RiskWraps(t *testing.T) {
+	// Far enough from the top left, the risk of a 9 goes all the way
+	// round past 9 more than once.
+	cave, err := ParseCave(strings.NewReader("9"), 7)
+	require.NoError(t, err)
+
+	// Indexed by how many tiles away from the top left each tile is.
+	expected := []int{9, 1, 2, 3, 4, 5, 6, 7, 8, 9, 1, 2, 3}
+	for _, node := range cave.chitons {
+		assert.Equal(t, expected[node.X+node.Y], node.risk, "risk at %v", node.Point)
+	}
+}
+
+func Test_NoReps(t *testing.T) {
+	_, err := ParseCave(strings.NewReader(testCase), 0)
+	assert.Error(t, err)
+}
+
 func Benchmark_FiveFold(b *testing.B) {
 	debug.SetGCPercent(-1)
 	r := strings.NewReader(testCase)
 
-	nodes, err := ParseNodes(r, 5)
+	cave, err := ParseCave(r, 5)
 	require.NoError(b, err)
-	source := nodes[0]
-	end := nodes[len(nodes)-1]
+	source := cave.start()
+	end := cave.end()
 	for i := 0; i < b.N; i++ {
 		path, err := FindPath(
 			source,
@@ -81,12 +99,12 @@ func Benchmark_FiveFold(b *testing.B) {
 			func(node Node) int {
 				n := node.(*chiton)
 
-				return (end.x - n.x) + (end.y - n.y)
+				return (end.X - n.X) + (end.Y - n.Y)
 			},
 		)
 
 		require.NoError(b, err)
-		assert.Equal(b, 315, Risk(nodes, path))
+		assert.Equal(b, 315, cave.Risk(path))
 	}
 
 }
@@ -1,6 +1,7 @@
 package day15
 
 import (
+	"context"
 	"errors"
 )
 
@@ -18,6 +19,13 @@ type Node interface {
 }
 
 func FindPath(source, end Node, heuristic func(Node) int) ([]ID, error) {
+	return FindPathContext(context.Background(), source, end, heuristic)
+}
+
+// FindPathContext is FindPath for graphs big enough that the caller may not
+// want to wait around for the search to finish. It checks ctx every time it
+// pops a node off the queue, which is cheap next to the work done on each.
+func FindPathContext(ctx context.Context, source, end Node, heuristic func(Node) int) ([]ID, error) {
 	dist := make(Distances)
 	prev := make(map[ID]ID)
 	var q MinHeap
@@ -26,6 +34,9 @@ func FindPath(source, end Node, heuristic func(Node) int) ([]ID, error) {
 	q.Update(source, 0)
 
 	for q.Len() > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 
 		cur := q.PopNode()
 		if cur.ID() == end.ID() {
@@ -26,34 +26,49 @@ type HeapItem struct {
 	index    int
 }
 
-type MinHeap []*HeapItem
+// MinHeap keeps track of where each node is in the heap by its ID, so that
+// updating the priority of a node already in the heap doesn't mean looking
+// through the whole heap to find it first. That only starts to matter once
+// the heap grows large, like it does searching through the arrangements of
+// amphipods on day 23.
+type MinHeap struct {
+	items []*HeapItem
+	byID  map[ID]*HeapItem
+}
 
-func (mh MinHeap) Len() int { return len(mh) }
+func (mh MinHeap) Len() int { return len(mh.items) }
 
 func (mh MinHeap) Less(i, j int) bool {
-	return mh[i].priority < mh[j].priority
+	return mh.items[i].priority < mh.items[j].priority
 }
 
 func (mh MinHeap) Swap(i, j int) {
-	mh[i], mh[j] = mh[j], mh[i]
-	mh[i].index = i
-	mh[j].index = j
+	mh.items[i], mh.items[j] = mh.items[j], mh.items[i]
+	mh.items[i].index = i
+	mh.items[j].index = j
 }
 
 func (mh *MinHeap) Push(x interface{}) {
-	n := len(*mh)
+	n := len(mh.items)
 	item := x.(*HeapItem)
 	item.index = n
-	*mh = append(*mh, item)
+	mh.items = append(mh.items, item)
+
+	if mh.byID == nil {
+		mh.byID = make(map[ID]*HeapItem)
+	}
+
+	mh.byID[item.value.ID()] = item
 }
 
 func (mh *MinHeap) Pop() interface{} {
-	old := *mh
+	old := mh.items
 	n := len(old)
 	item := old[n-1]
 	old[n-1] = nil
 	item.index = -1
-	*mh = old[0 : n-1]
+	mh.items = old[0 : n-1]
+	delete(mh.byID, item.value.ID())
 	return item
 }
 
@@ -65,11 +80,7 @@ func (mh *MinHeap) PopNode() Node {
 }
 
 func (mh *MinHeap) Update(node Node, priority int) {
-	for _, item := range *mh {
-		if item.value.ID() != node.ID() {
-			continue
-		}
-
+	if item, ok := mh.byID[node.ID()]; ok {
 		item.priority = priority
 		heap.Fix(mh, item.index)
 		return
@@ -77,7 +88,7 @@ func (mh *MinHeap) Update(node Node, priority int) {
 
 	item := &HeapItem{
 		value:    node,
-		index:    len(*mh),
+		index:    len(mh.items),
 		priority: priority,
 	}
 
@@ -2,34 +2,76 @@ package day15
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  15,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
+var _REPS = registry.Param{
+	Name:    "reps",
+	Usage:   "times the map is repeated across and down in part two",
+	Default: 5,
+	Check:   registry.AtLeast(1),
+}
+
 type Solution struct {
-	first  map[int]*chiton
-	second map[int]*chiton
+	first  *Cave
+	second *Cave
+	args   registry.Args
 }
 
 func (s *Solution) Name() string {
 	return "Chiton"
 }
 
+// ConcurrentParts holds since each part walks its own graph.
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
+func (s *Solution) Params() []registry.Param {
+	return []registry.Param{_REPS}
+}
+
+func (s *Solution) SetArgs(args registry.Args) {
+	s.args = args
+}
+
 func (s *Solution) Load(r io.Reader) error {
+	return s.LoadContext(context.Background(), r)
+}
+
+func (s *Solution) LoadContext(ctx context.Context, r io.Reader) error {
 	var b1, b2 bytes.Buffer
 	w := io.MultiWriter(&b1, &b2)
 	if _, err := io.Copy(w, r); err != nil {
 		return err
 	}
 
-	first, err := ParseNodes(&b1, 1)
+	first, err := ParseCave(&b1, 1)
 	if err != nil {
 		return err
 	}
 
 	s.first = first
 
-	second, err := ParseNodes(&b2, 5)
+	// Parsing the grid five times over takes a while on its own, so
+	// don't start on it if we've already run out of time.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	second, err := ParseCave(&b2, s.args.Int(_REPS))
 	if err != nil {
 		return err
 	}
@@ -39,35 +81,31 @@ func (s *Solution) Load(r io.Reader) error {
 }
 
 func (s *Solution) PartOne() (string, error) {
-	source := s.first[0]
-	end := s.first[len(s.first)-1]
-	path, err := FindPath(
-		source,
-		end,
-		func(node Node) int {
-			n := node.(*chiton)
-
-			return (end.x - n.x) + (end.y - n.y)
-		},
-	)
+	return s.PartOneContext(context.Background())
+}
 
-	if err != nil {
-		return "", err
-	}
-	score := Risk(s.first, path)
-	return strconv.Itoa(score), nil
+func (s *Solution) PartOneContext(ctx context.Context) (string, error) {
+	return lowestRisk(ctx, s.first)
 }
 
 func (s *Solution) PartTwo() (string, error) {
-	source := s.second[0]
-	end := s.second[len(s.second)-1]
-	path, err := FindPath(
-		source,
+	return s.PartTwoContext(context.Background())
+}
+
+func (s *Solution) PartTwoContext(ctx context.Context) (string, error) {
+	return lowestRisk(ctx, s.second)
+}
+
+func lowestRisk(ctx context.Context, cave *Cave) (string, error) {
+	end := cave.end()
+	path, err := FindPathContext(
+		ctx,
+		cave.start(),
 		end,
 		func(node Node) int {
 			n := node.(*chiton)
 
-			return (end.x - n.x) + (end.y - n.y)
+			return (end.X - n.X) + (end.Y - n.Y)
 		},
 	)
 
@@ -75,6 +113,6 @@ func (s *Solution) PartTwo() (string, error) {
 		return "", err
 	}
 
-	score := Risk(s.second, path)
+	score := cave.Risk(path)
 	return strconv.Itoa(score), nil
 }
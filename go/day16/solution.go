@@ -3,8 +3,18 @@ package day16
 import (
 	"io"
 	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  16,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
 type Solution struct {
 	root Packet
 }
@@ -13,6 +23,10 @@ func (s *Solution) Name() string {
 	return "Packet Decoder"
 }
 
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
 func (s *Solution) Load(r io.Reader) error {
 	packet, err := Parse(r)
 	if err != nil {
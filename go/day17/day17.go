@@ -0,0 +1,181 @@
+package day17
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// Target is the trench the probe has to end up in, inclusive on all sides.
+type Target struct {
+	MinX, MaxX int
+	MinY, MaxY int
+}
+
+func ParseTarget(r io.Reader) (Target, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Target{}, err
+	}
+
+	var t Target
+	if _, err := fmt.Sscanf(
+		strings.TrimSpace(string(b)),
+		"target area: x=%d..%d, y=%d..%d",
+		&t.MinX, &t.MaxX, &t.MinY, &t.MaxY,
+	); err != nil {
+		return Target{}, fmt.Errorf("expected target area: x=a..b, y=c..d: %w", err)
+	}
+
+	if t.MinX > t.MaxX {
+		t.MinX, t.MaxX = t.MaxX, t.MinX
+	}
+
+	if t.MinY > t.MaxY {
+		t.MinY, t.MaxY = t.MaxY, t.MinY
+	}
+
+	// Every puzzle input puts the trench below and to the right of the
+	// launcher and the bounds on the search below lean on that. Rather
+	// than quietly coming up with nonsense for any other kind of target
+	// we'd rather just refuse it.
+	if t.MinX <= 0 || t.MaxY >= 0 {
+		return Target{}, errors.New("the target area must be below and to the right of the launcher")
+	}
+
+	return t, nil
+}
+
+func (t Target) Contains(x, y int) bool {
+	return t.MinX <= x && x <= t.MaxX && t.MinY <= y && y <= t.MaxY
+}
+
+// Missed reports whether the probe can never make it into the target. Drag
+// only ever slows the probe down towards the right and gravity only ever
+// pulls it down, so once it's past the far side or falling beneath the
+// bottom there's no coming back.
+func (t Target) Missed(p Probe) bool {
+	return p.X > t.MaxX || (p.Y < t.MinY && p.VY < 0)
+}
+
+// Probe is the position and velocity of the probe at a single step.
+type Probe struct {
+	X, Y   int
+	VX, VY int
+}
+
+func Launch(vx, vy int) Probe {
+	return Probe{VX: vx, VY: vy}
+}
+
+// Step moves the probe along by its velocity and then has drag and gravity
+// take their toll on that velocity.
+func (p Probe) Step() Probe {
+	p.X += p.VX
+	p.Y += p.VY
+
+	switch {
+	case p.VX > 0:
+		p.VX--
+	case p.VX < 0:
+		p.VX++
+	}
+
+	p.VY--
+
+	return p
+}
+
+// Trajectory returns every step of the probe's flight, starting at the
+// launcher, up until it either lands in the target or misses it for good.
+func Trajectory(vx, vy int, t Target) ([]Probe, bool) {
+	probe := Launch(vx, vy)
+	path := []Probe{probe}
+
+	for !t.Missed(probe) {
+		probe = probe.Step()
+		path = append(path, probe)
+
+		if t.Contains(probe.X, probe.Y) {
+			return path, true
+		}
+	}
+
+	return path, false
+}
+
+// Fire flies the probe just like Trajectory but only keeps track of the
+// highest point it reached along the way.
+func Fire(vx, vy int, t Target) (peak int, hit bool) {
+	probe := Launch(vx, vy)
+
+	for !t.Missed(probe) {
+		probe = probe.Step()
+		if probe.Y > peak {
+			peak = probe.Y
+		}
+
+		if t.Contains(probe.X, probe.Y) {
+			return peak, true
+		}
+	}
+
+	return peak, false
+}
+
+// Bounds narrows down the launch velocities worth trying to the ones that
+// could conceivably hit the target, which is what keeps the searches below
+// from being blind brute force.
+//
+// Horizontally the probe travels vx + (vx-1) + ... + 1 = vx(vx+1)/2 before
+// drag stops it dead, so any vx too small for that to reach the near side of
+// the target never gets there, and any vx beyond the far side overshoots it
+// on the very first step.
+//
+// Vertically a probe launched upwards at vy comes back down through y=0
+// with a velocity of -(vy+1), so its next step takes it to -(vy+1). Anything
+// faster than -MinY-1 therefore skips straight over the target on the way
+// down, and anything slower than MinY overshoots it on the first step.
+func (t Target) Bounds() (minVX, maxVX, minVY, maxVY int) {
+	for minVX*(minVX+1)/2 < t.MinX {
+		minVX++
+	}
+
+	return minVX, t.MaxX, t.MinY, -t.MinY - 1
+}
+
+// HighestPeak finds the highest point any probe reaches on its way to the
+// target. The faster the probe is launched upwards the higher it goes, so
+// working down from the fastest candidate the first hit is the answer.
+func HighestPeak(t Target) (int, bool) {
+	minVX, maxVX, minVY, maxVY := t.Bounds()
+
+	for vy := maxVY; vy >= minVY; vy-- {
+		for vx := minVX; vx <= maxVX; vx++ {
+			if peak, hit := Fire(vx, vy, t); hit {
+				return peak, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// CountVelocities counts every distinct launch velocity that hits the
+// target.
+func CountVelocities(t Target) int {
+	minVX, maxVX, minVY, maxVY := t.Bounds()
+
+	var count int
+	for vy := minVY; vy <= maxVY; vy++ {
+		for vx := minVX; vx <= maxVX; vx++ {
+			if _, hit := Fire(vx, vy, t); hit {
+				count++
+			}
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,52 @@
+package day17
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCase = `target area: x=20..30, y=-10..-5`
+
+func Test_ParseTarget(t *testing.T) {
+	target, err := ParseTarget(strings.NewReader(testCase))
+	require.NoError(t, err)
+	assert.Equal(t, Target{MinX: 20, MaxX: 30, MinY: -10, MaxY: -5}, target)
+
+	_, err = ParseTarget(strings.NewReader("target area: x=20..30, y=5..10"))
+	assert.Error(t, err)
+}
+
+func Test_Trajectory(t *testing.T) {
+	target, err := ParseTarget(strings.NewReader(testCase))
+	require.NoError(t, err)
+
+	path, hit := Trajectory(7, 2, target)
+	require.True(t, hit)
+	assert.Len(t, path, 8)
+	assert.Equal(t, Probe{X: 28, Y: -7, VX: 0, VY: -5}, path[len(path)-1])
+
+	for _, tt := range []struct {
+		vx, vy int
+		hit    bool
+	}{
+		{6, 3, true},
+		{9, 0, true},
+		{17, -4, false},
+	} {
+		_, hit := Fire(tt.vx, tt.vy, target)
+		assert.Equal(t, tt.hit, hit)
+	}
+}
+
+func Test_Example(t *testing.T) {
+	target, err := ParseTarget(strings.NewReader(testCase))
+	require.NoError(t, err)
+
+	peak, ok := HighestPeak(target)
+	require.True(t, ok)
+	assert.Equal(t, 45, peak)
+	assert.Equal(t, 112, CountVelocities(target))
+}
@@ -0,0 +1,78 @@
+package day17
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  17,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
+type Solution struct {
+	target Target
+}
+
+func (s *Solution) Name() string {
+	return "Trick Shot"
+}
+
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
+func (s *Solution) Load(r io.Reader) error {
+	target, err := ParseTarget(r)
+	if err != nil {
+		return err
+	}
+
+	s.target = target
+
+	return nil
+}
+
+func (s *Solution) PartOne() (string, error) {
+	peak, ok := HighestPeak(s.target)
+	if !ok {
+		return "", errors.New("no launch velocity reaches the target")
+	}
+
+	return strconv.Itoa(peak), nil
+}
+
+func (s *Solution) PartTwo() (string, error) {
+	return strconv.Itoa(CountVelocities(s.target)), nil
+}
+
+func (s *Solution) Operations() []registry.Operation {
+	return []registry.Operation{
+		{
+			Name:  "Fire",
+			Args:  []string{"vx", "vy"},
+			Usage: "launch the probe and report whether it hits and how high it gets",
+			Call: func(args []int) (string, error) {
+				path, hit := Trajectory(args[0], args[1], s.target)
+
+				var peak int
+				for _, probe := range path {
+					if probe.Y > peak {
+						peak = probe.Y
+					}
+				}
+
+				last := path[len(path)-1]
+
+				return fmt.Sprintf("hit=%v peak=%v steps=%v last=%v,%v", hit, peak, len(path)-1, last.X, last.Y), nil
+			},
+		},
+	}
+}
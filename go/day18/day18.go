@@ -0,0 +1,293 @@
+package day18
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// Number is a snailfish number, which is either a regular number or a pair
+// of snailfish numbers.
+//
+// Numbers are immutable. Adding and reducing never touch the Numbers they're
+// given, they build new ones that share every subtree that didn't change.
+// That costs a few allocations per step of reduction, but it means that the
+// same Number can be added to any number of others, even all at once, which
+// is exactly what the search for the largest sum in part two wants to do.
+type Number struct {
+	left, right *Number
+	value       int
+}
+
+func Regular(value int) *Number {
+	return &Number{value: value}
+}
+
+func Pair(left, right *Number) *Number {
+	return &Number{left: left, right: right}
+}
+
+func (n *Number) IsRegular() bool {
+	return n.left == nil
+}
+
+// Value is the value of a regular number. Pairs have no value of their own,
+// what they have is a Magnitude.
+func (n *Number) Value() int {
+	return n.value
+}
+
+func (n *Number) Left() *Number {
+	return n.left
+}
+
+func (n *Number) Right() *Number {
+	return n.right
+}
+
+func (n *Number) String() string {
+	if n.IsRegular() {
+		return strconv.Itoa(n.value)
+	}
+
+	return fmt.Sprintf("[%v,%v]", n.left, n.right)
+}
+
+func (n *Number) Magnitude() int {
+	if n.IsRegular() {
+		return n.value
+	}
+
+	return 3*n.left.Magnitude() + 2*n.right.Magnitude()
+}
+
+// Add returns the reduced sum of a and b.
+func Add(a, b *Number) *Number {
+	return Reduce(Pair(a, b))
+}
+
+// Reduce explodes and splits n until there's nothing left to explode or
+// split, always exploding when there's a choice between the two.
+func Reduce(n *Number) *Number {
+	for {
+		if exploded, _, _, ok := n.explode(0); ok {
+			n = exploded
+			continue
+		}
+
+		if split, ok := n.split(); ok {
+			n = split
+			continue
+		}
+
+		return n
+	}
+}
+
+// explode replaces the leftmost pair nested inside four other pairs with a
+// regular 0, returning the new number and what's left of the values of the
+// exploded pair that still need adding to the regular numbers either side
+// of it. By the time a value makes it back out to the top without finding
+// a regular number to land on it simply falls off the edge.
+func (n *Number) explode(depth int) (*Number, int, int, bool) {
+	if n.IsRegular() {
+		return n, 0, 0, false
+	}
+
+	if depth >= 4 && n.left.IsRegular() && n.right.IsRegular() {
+		return Regular(0), n.left.value, n.right.value, true
+	}
+
+	if left, l, r, ok := n.left.explode(depth + 1); ok {
+		return Pair(left, n.right.addLeftmost(r)), l, 0, true
+	}
+
+	if right, l, r, ok := n.right.explode(depth + 1); ok {
+		return Pair(n.left.addRightmost(l), right), 0, r, true
+	}
+
+	return n, 0, 0, false
+}
+
+func (n *Number) addLeftmost(value int) *Number {
+	if value == 0 {
+		return n
+	}
+
+	if n.IsRegular() {
+		return Regular(n.value + value)
+	}
+
+	return Pair(n.left.addLeftmost(value), n.right)
+}
+
+func (n *Number) addRightmost(value int) *Number {
+	if value == 0 {
+		return n
+	}
+
+	if n.IsRegular() {
+		return Regular(n.value + value)
+	}
+
+	return Pair(n.left, n.right.addRightmost(value))
+}
+
+// split replaces the leftmost regular number of 10 or more with a pair of
+// its halves, rounding the left half down and the right half up.
+func (n *Number) split() (*Number, bool) {
+	if n.IsRegular() {
+		if n.value < 10 {
+			return n, false
+		}
+
+		return Pair(Regular(n.value/2), Regular((n.value+1)/2)), true
+	}
+
+	if left, ok := n.left.split(); ok {
+		return Pair(left, n.right), true
+	}
+
+	if right, ok := n.right.split(); ok {
+		return Pair(n.left, right), true
+	}
+
+	return n, false
+}
+
+// Sum adds up every number in order, which matters since snailfish addition
+// is anything but commutative.
+func Sum(numbers []*Number) *Number {
+	if len(numbers) == 0 {
+		return nil
+	}
+
+	sum := numbers[0]
+	for _, n := range numbers[1:] {
+		sum = Add(sum, n)
+	}
+
+	return sum
+}
+
+// LargestSum finds the largest magnitude of the sum of any two different
+// numbers, in either order. Every number gets its own goroutine to try it
+// as the left hand side against everything else, which is only safe
+// because nothing in here ever modifies a Number.
+func LargestSum(numbers []*Number) int {
+	best := make([]int, len(numbers))
+
+	var wg sync.WaitGroup
+	for i := range numbers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for j := range numbers {
+				if i == j {
+					continue
+				}
+
+				if m := Add(numbers[i], numbers[j]).Magnitude(); m > best[i] {
+					best[i] = m
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	var max int
+	for _, m := range best {
+		if m > max {
+			max = m
+		}
+	}
+
+	return max
+}
+
+func Parse(s string) (*Number, error) {
+	n, rest, err := parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if rest != "" {
+		return nil, fmt.Errorf("unexpected %q after snailfish number", rest)
+	}
+
+	return n, nil
+}
+
+// parse reads a single snailfish number off the front of s and returns it
+// along with whatever's left of s after it.
+func parse(s string) (*Number, string, error) {
+	if s == "" {
+		return nil, "", io.ErrUnexpectedEOF
+	}
+
+	if s[0] != '[' {
+		end := 0
+		for end < len(s) && '0' <= s[end] && s[end] <= '9' {
+			end++
+		}
+
+		if end == 0 {
+			return nil, "", fmt.Errorf("unexpected %q, expected a number or a pair", s[0])
+		}
+
+		value, err := strconv.Atoi(s[:end])
+		if err != nil {
+			return nil, "", err
+		}
+
+		return Regular(value), s[end:], nil
+	}
+
+	left, rest, err := parse(s[1:])
+	if err != nil {
+		return nil, "", err
+	}
+
+	if rest == "" || rest[0] != ',' {
+		return nil, "", fmt.Errorf("expected ',' between the two halves of a pair")
+	}
+
+	right, rest, err := parse(rest[1:])
+	if err != nil {
+		return nil, "", err
+	}
+
+	if rest == "" || rest[0] != ']' {
+		return nil, "", fmt.Errorf("expected ']' at the end of a pair")
+	}
+
+	return Pair(left, right), rest[1:], nil
+}
+
+func ParseNumbers(r io.Reader) ([]*Number, error) {
+	scanner := bufio.NewScanner(r)
+
+	var numbers []*Number
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			continue
+		}
+
+		n, err := Parse(scanner.Text())
+		if err != nil {
+			return nil, err
+		}
+
+		numbers = append(numbers, n)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return numbers, nil
+}
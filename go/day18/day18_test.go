@@ -0,0 +1,87 @@
+package day18
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCase = `[[[0,[5,8]],[[1,7],[9,6]]],[[4,[1,2]],[[1,4],2]]]
+[[[5,[2,8]],4],[5,[[9,9],0]]]
+[6,[[[6,2],[5,6]],[[7,6],[4,7]]]]
+[[[6,[0,7]],[0,9]],[4,[9,[9,0]]]]
+[[[7,[6,4]],[3,[1,3]]],[[[5,5],1],9]]
+[[6,[[7,3],[3,2]]],[[[3,8],[5,7]],4]]
+[[[[5,4],[7,7]],8],[[8,3],8]]
+[[9,3],[[9,9],[6,[4,9]]]]
+[[2,[[7,7],7]],[[5,8],[[9,3],[0,2]]]]
+[[[[5,2],5],[8,[3,7]]],[[5,[7,5]],[4,4]]]`
+
+func mustParse(t *testing.T, s string) *Number {
+	n, err := Parse(s)
+	require.NoError(t, err)
+	require.Equal(t, s, n.String())
+
+	return n
+}
+
+func Test_Parse(t *testing.T) {
+	for _, bad := range []string{"", "[1,2", "[1;2]", "[1,2]]", "[,2]"} {
+		_, err := Parse(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func Test_Explode(t *testing.T) {
+	for _, tt := range []struct {
+		number   string
+		expected string
+	}{
+		{"[[[[[9,8],1],2],3],4]", "[[[[0,9],2],3],4]"},
+		{"[7,[6,[5,[4,[3,2]]]]]", "[7,[6,[5,[7,0]]]]"},
+		{"[[6,[5,[4,[3,2]]]],1]", "[[6,[5,[7,0]]],3]"},
+		{"[[3,[2,[1,[7,3]]]],[6,[5,[4,[3,2]]]]]", "[[3,[2,[8,0]]],[9,[5,[4,[3,2]]]]]"},
+	} {
+		t.Run(tt.number, func(t *testing.T) {
+			n := mustParse(t, tt.number)
+
+			exploded, _, _, ok := n.explode(0)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, exploded.String())
+
+			// The original is left just the way it was.
+			assert.Equal(t, tt.number, n.String())
+		})
+	}
+}
+
+func Test_Add(t *testing.T) {
+	a := mustParse(t, "[[[[4,3],4],4],[7,[[8,4],9]]]")
+	b := mustParse(t, "[1,1]")
+
+	assert.Equal(t, "[[[[0,7],4],[[7,8],[6,0]]],[8,1]]", Add(a, b).String())
+
+	var numbers []*Number
+	for _, s := range []string{"[1,1]", "[2,2]", "[3,3]", "[4,4]", "[5,5]", "[6,6]"} {
+		numbers = append(numbers, mustParse(t, s))
+	}
+
+	assert.Equal(t, "[[[[5,0],[7,4]],[5,5]],[6,6]]", Sum(numbers).String())
+}
+
+func Test_Magnitude(t *testing.T) {
+	assert.Equal(t, 143, mustParse(t, "[[1,2],[[3,4],5]]").Magnitude())
+	assert.Equal(t, 3488, mustParse(t, "[[[[8,7],[7,7]],[[8,6],[7,7]]],[[[0,7],[6,6]],[8,7]]]").Magnitude())
+}
+
+func Test_Example(t *testing.T) {
+	numbers, err := ParseNumbers(strings.NewReader(testCase))
+	require.NoError(t, err)
+
+	sum := Sum(numbers)
+	assert.Equal(t, "[[[[6,6],[7,6]],[[7,7],[7,0]]],[[[7,7],[7,7]],[[7,8],[9,9]]]]", sum.String())
+	assert.Equal(t, 4140, sum.Magnitude())
+	assert.Equal(t, 3993, LargestSum(numbers))
+}
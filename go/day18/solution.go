@@ -0,0 +1,54 @@
+package day18
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  18,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
+type Solution struct {
+	numbers []*Number
+}
+
+func (s *Solution) Name() string {
+	return "Snailfish"
+}
+
+// ConcurrentParts holds since Numbers are immutable, so neither part can
+// change the homework out from under the other.
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
+func (s *Solution) Load(r io.Reader) error {
+	numbers, err := ParseNumbers(r)
+	if err != nil {
+		return err
+	}
+
+	if len(numbers) == 0 {
+		return errors.New("no snailfish numbers in the homework")
+	}
+
+	s.numbers = numbers
+
+	return nil
+}
+
+func (s *Solution) PartOne() (string, error) {
+	return strconv.Itoa(Sum(s.numbers).Magnitude()), nil
+}
+
+func (s *Solution) PartTwo() (string, error) {
+	return strconv.Itoa(LargestSum(s.numbers)), nil
+}
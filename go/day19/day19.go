@@ -0,0 +1,244 @@
+package day19
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// _OVERLAP is how many beacons two scanners have to agree on before we'll
+// believe they're looking at the same part of the ocean.
+const _OVERLAP = 12
+
+// Scanner is everything one scanner reported. Until it has been aligned
+// with scanner 0 its Beacons are relative to itself, in whichever
+// orientation it happened to be facing. Once it has been aligned they're
+// relative to scanner 0 instead, and so is its Position.
+type Scanner struct {
+	ID       int
+	Beacons  []Vector
+	Position Vector
+	Aligned  bool
+
+	// distances fingerprints the scanner by the squared distance
+	// between every pair of beacons it can see, which doesn't depend on
+	// where the scanner is or which way it's facing. Each distance maps
+	// onto the pairs of beacons, by index, that are that far apart.
+	distances map[int][][2]int
+}
+
+func NewScanner(id int, beacons []Vector) *Scanner {
+	s := &Scanner{
+		ID:        id,
+		Beacons:   beacons,
+		distances: make(map[int][][2]int),
+	}
+
+	for i := range beacons {
+		for j := i + 1; j < len(beacons); j++ {
+			d := beacons[i].Sub(beacons[j]).SquaredNorm()
+			s.distances[d] = append(s.distances[d], [2]int{i, j})
+		}
+	}
+
+	return s
+}
+
+// candidates picks out the beacons of a and b that could possibly be the
+// same beacons, going by their fingerprints. Two scanners that share at
+// least _OVERLAP beacons share at least _OVERLAP choose 2 distances between
+// them, so any fewer than that and there's no point going any further.
+func candidates(a, b *Scanner) ([]int, []int, bool) {
+	var shared int
+	inA := make(map[int]bool)
+	inB := make(map[int]bool)
+
+	for d, pairs := range a.distances {
+		others, ok := b.distances[d]
+		if !ok {
+			continue
+		}
+
+		shared += len(pairs)
+		for _, pair := range pairs {
+			inA[pair[0]], inA[pair[1]] = true, true
+		}
+
+		for _, pair := range others {
+			inB[pair[0]], inB[pair[1]] = true, true
+		}
+	}
+
+	if shared < _OVERLAP*(_OVERLAP-1)/2 {
+		return nil, nil, false
+	}
+
+	keys := func(m map[int]bool) []int {
+		out := make([]int, 0, len(m))
+		for k := range m {
+			out = append(out, k)
+		}
+
+		return out
+	}
+
+	return keys(inA), keys(inB), true
+}
+
+// Align tries to find the Rotation and offset that, applied in that order,
+// carry the beacons of b into the frame of a. It only ever has to consider
+// the beacons the fingerprints single out rather than every pairing of the
+// beacons of both scanners.
+func Align(a, b *Scanner) (Rotation, Vector, bool) {
+	ai, bi, ok := candidates(a, b)
+	if !ok {
+		return Rotation{}, Vector{}, false
+	}
+
+	for _, r := range _ROTATIONS {
+		counts := make(map[Vector]int)
+		for _, j := range bi {
+			rotated := r.Apply(b.Beacons[j])
+			for _, i := range ai {
+				offset := a.Beacons[i].Sub(rotated)
+
+				counts[offset]++
+				if counts[offset] >= _OVERLAP {
+					return r, offset, true
+				}
+			}
+		}
+	}
+
+	return Rotation{}, Vector{}, false
+}
+
+// Transform moves the scanner, beacons and all, into the frame of scanner
+// 0 given how it was aligned. The fingerprint doesn't change since that's
+// the whole point of it.
+func (s *Scanner) Transform(r Rotation, offset Vector) {
+	for i, beacon := range s.Beacons {
+		s.Beacons[i] = r.Apply(beacon).Add(offset)
+	}
+
+	s.Position = offset
+	s.Aligned = true
+}
+
+// Assemble aligns every scanner with scanner 0, working outwards one
+// scanner at a time from the scanners that have already been aligned. Every
+// scanner is only ever compared against each aligned scanner once.
+func Assemble(scanners []*Scanner) error {
+	if len(scanners) == 0 {
+		return errors.New("no scanners")
+	}
+
+	scanners[0].Aligned = true
+	queue := []*Scanner{scanners[0]}
+
+	for len(queue) > 0 {
+		known := queue[0]
+		queue = queue[1:]
+
+		for _, s := range scanners {
+			if s.Aligned {
+				continue
+			}
+
+			if r, offset, ok := Align(known, s); ok {
+				s.Transform(r, offset)
+				queue = append(queue, s)
+			}
+		}
+	}
+
+	for _, s := range scanners {
+		if !s.Aligned {
+			return fmt.Errorf("scanner %v doesn't overlap with any other scanner", s.ID)
+		}
+	}
+
+	return nil
+}
+
+// Beacons returns every distinct beacon the assembled scanners can see
+// between them.
+func Beacons(scanners []*Scanner) map[Vector]bool {
+	beacons := make(map[Vector]bool)
+	for _, s := range scanners {
+		for _, beacon := range s.Beacons {
+			beacons[beacon] = true
+		}
+	}
+
+	return beacons
+}
+
+// Spread is the largest Manhattan distance between any two of the assembled
+// scanners.
+func Spread(scanners []*Scanner) int {
+	var max int
+	for i, a := range scanners {
+		for _, b := range scanners[i+1:] {
+			if d := a.Position.Manhattan(b.Position); d > max {
+				max = d
+			}
+		}
+	}
+
+	return max
+}
+
+func ParseScanners(r io.Reader) ([]*Scanner, error) {
+	scanner := bufio.NewScanner(r)
+
+	var (
+		scanners []*Scanner
+		beacons  []Vector
+		id       = -1
+	)
+
+	flush := func() {
+		if id >= 0 {
+			scanners = append(scanners, NewScanner(id, beacons))
+		}
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "---"):
+			flush()
+
+			if _, err := fmt.Sscanf(line, "--- scanner %d ---", &id); err != nil {
+				return nil, fmt.Errorf("invalid scanner header %q: %w", line, err)
+			}
+
+			beacons = nil
+		default:
+			if id < 0 {
+				return nil, errors.New("expected a scanner header before any beacons")
+			}
+
+			var v Vector
+			if _, err := fmt.Sscanf(line, "%d,%d,%d", &v.X, &v.Y, &v.Z); err != nil {
+				return nil, fmt.Errorf("invalid beacon %q: %w", line, err)
+			}
+
+			beacons = append(beacons, v)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	flush()
+
+	return scanners, nil
+}
@@ -0,0 +1,107 @@
+package day19
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCase = `--- scanner 0 ---
+404,-588,-901
+528,-643,409
+
+--- scanner 1 ---
+686,422,578
+605,423,415
+515,917,-361`
+
+func Test_ParseScanners(t *testing.T) {
+	scanners, err := ParseScanners(strings.NewReader(testCase))
+	require.NoError(t, err)
+	require.Len(t, scanners, 2)
+
+	assert.Equal(t, 1, scanners[1].ID)
+	assert.Equal(t, []Vector{{404, -588, -901}, {528, -643, 409}}, scanners[0].Beacons)
+	assert.Len(t, scanners[1].Beacons, 3)
+	assert.Len(t, scanners[1].distances, 3)
+}
+
+func Test_Rotations(t *testing.T) {
+	rotations := Rotations()
+	require.Len(t, rotations, 24)
+	assert.Equal(t, Identity, rotations[0])
+
+	v := Vector{1, 2, 3}
+
+	seen := make(map[Vector]bool)
+	for _, r := range rotations {
+		seen[r.Apply(v)] = true
+		assert.Equal(t, v.SquaredNorm(), r.Apply(v).SquaredNorm())
+
+		// The inverse of a rotation is its transpose, and the two
+		// of them together should get us right back where we were.
+		assert.Equal(t, Identity, r.Compose(transpose(r)))
+	}
+
+	assert.Len(t, seen, 24)
+}
+
+func transpose(r Rotation) Rotation {
+	var out Rotation
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			out[i][j] = r[j][i]
+		}
+	}
+
+	return out
+}
+
+// report writes out what a scanner at position facing the given way would
+// see of the beacons, in the same format as the puzzle input.
+func report(id int, beacons []Vector, position Vector, facing Rotation) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- scanner %v ---\n", id)
+	for _, beacon := range beacons {
+		fmt.Fprintln(&b, transpose(facing).Apply(beacon.Sub(position)))
+	}
+
+	return b.String()
+}
+
+func Test_Assemble(t *testing.T) {
+	// A cheap and cheerful pseudo random scattering of beacons, which
+	// needs no more than to not be too regular.
+	var beacons []Vector
+	seed := 17
+	next := func() int {
+		seed = (seed*1103515245 + 12345) % 2147483648
+		return seed%1800 - 900
+	}
+
+	for i := 0; i < 40; i++ {
+		beacons = append(beacons, Vector{next(), next(), next()})
+	}
+
+	position := Vector{68, -1246, -43}
+	facing := Rotations()[17]
+
+	input := report(0, beacons[:28], Vector{}, Identity) + "\n" + report(1, beacons[12:], position, facing)
+
+	scanners, err := ParseScanners(strings.NewReader(input))
+	require.NoError(t, err)
+	require.NoError(t, Assemble(scanners))
+
+	assert.Equal(t, position, scanners[1].Position)
+	assert.Equal(t, beacons[12:], scanners[1].Beacons)
+	assert.Len(t, Beacons(scanners), 40)
+	assert.Equal(t, 68+1246+43, Spread(scanners))
+
+	lonely := report(2, []Vector{{1, 2, 3}}, Vector{}, Identity)
+	scanners, err = ParseScanners(strings.NewReader(input + "\n" + lonely))
+	require.NoError(t, err)
+	assert.Error(t, Assemble(scanners))
+}
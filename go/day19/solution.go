@@ -0,0 +1,56 @@
+package day19
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  19,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
+type Solution struct {
+	scanners []*Scanner
+}
+
+func (s *Solution) Name() string {
+	return "Beacon Scanner"
+}
+
+// ConcurrentParts holds since both parts only read the scanners that were
+// already assembled by Load.
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
+// Load doesn't stop at parsing the reports. Both parts need the full map of
+// the ocean, and rather than assemble it twice, or have one part depend on
+// the other having run first, it gets assembled once here.
+func (s *Solution) Load(r io.Reader) error {
+	scanners, err := ParseScanners(r)
+	if err != nil {
+		return err
+	}
+
+	if err := Assemble(scanners); err != nil {
+		return err
+	}
+
+	s.scanners = scanners
+
+	return nil
+}
+
+func (s *Solution) PartOne() (string, error) {
+	return strconv.Itoa(len(Beacons(s.scanners))), nil
+}
+
+func (s *Solution) PartTwo() (string, error) {
+	return strconv.Itoa(Spread(s.scanners)), nil
+}
@@ -0,0 +1,113 @@
+package day19
+
+import "fmt"
+
+// Vector is a point, or the offset between two points, in 3D space.
+type Vector struct {
+	X, Y, Z int
+}
+
+func (v Vector) Add(o Vector) Vector {
+	return Vector{v.X + o.X, v.Y + o.Y, v.Z + o.Z}
+}
+
+func (v Vector) Sub(o Vector) Vector {
+	return Vector{v.X - o.X, v.Y - o.Y, v.Z - o.Z}
+}
+
+// Manhattan is the taxicab distance between v and o.
+func (v Vector) Manhattan(o Vector) int {
+	d := v.Sub(o)
+	return abs(d.X) + abs(d.Y) + abs(d.Z)
+}
+
+// SquaredNorm is the squared length of v. Unlike the Manhattan distance it
+// doesn't change when v is rotated, which is what makes it useful for
+// recognizing the same pair of beacons from two different scanners.
+func (v Vector) SquaredNorm() int {
+	return v.X*v.X + v.Y*v.Y + v.Z*v.Z
+}
+
+func (v Vector) String() string {
+	return fmt.Sprintf("%v,%v,%v", v.X, v.Y, v.Z)
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+
+	return i
+}
+
+// Rotation is a rotation by some multiple of 90 degrees around each axis,
+// written as the matrix it multiplies a Vector by.
+type Rotation [3][3]int
+
+// Identity is the Rotation that leaves everything where it is.
+var Identity = Rotation{
+	{1, 0, 0},
+	{0, 1, 0},
+	{0, 0, 1},
+}
+
+func (r Rotation) Apply(v Vector) Vector {
+	return Vector{
+		r[0][0]*v.X + r[0][1]*v.Y + r[0][2]*v.Z,
+		r[1][0]*v.X + r[1][1]*v.Y + r[1][2]*v.Z,
+		r[2][0]*v.X + r[2][1]*v.Y + r[2][2]*v.Z,
+	}
+}
+
+// Compose returns the Rotation that applies o and then r.
+func (r Rotation) Compose(o Rotation) Rotation {
+	var out Rotation
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				out[i][j] += r[i][k] * o[k][j]
+			}
+		}
+	}
+
+	return out
+}
+
+func (r Rotation) determinant() int {
+	return r[0][0]*(r[1][1]*r[2][2]-r[1][2]*r[2][1]) -
+		r[0][1]*(r[1][0]*r[2][2]-r[1][2]*r[2][0]) +
+		r[0][2]*(r[1][0]*r[2][1]-r[1][1]*r[2][0])
+}
+
+// _ROTATIONS are the 24 ways a scanner can be facing. Each one maps the
+// three axes onto some permutation of themselves, each possibly flipped,
+// which gives 48 matrices. Half of those are mirror images though, and
+// what sets the 24 genuine rotations apart is a determinant of +1.
+var _ROTATIONS = func() []Rotation {
+	var rotations []Rotation
+	for _, perm := range [][3]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}} {
+		for signs := 0; signs < 8; signs++ {
+			var r Rotation
+			for row, col := range perm {
+				r[row][col] = 1
+				if signs&(1<<row) != 0 {
+					r[row][col] = -1
+				}
+			}
+
+			if r.determinant() == 1 {
+				rotations = append(rotations, r)
+			}
+		}
+	}
+
+	return rotations
+}()
+
+// Rotations returns all 24 Rotations, starting with the Identity.
+func Rotations() []Rotation {
+	out := make([]Rotation, len(_ROTATIONS))
+	copy(out, _ROTATIONS)
+
+	return out
+}
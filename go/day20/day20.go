@@ -0,0 +1,214 @@
+package day20
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/stntngo/advent-2021/go/grid"
+)
+
+// Algorithm is the image enhancement algorithm: whether the output pixel is
+// lit for each of the 512 possible 3x3 neighborhoods around it, read top
+// left to bottom right as a binary number.
+type Algorithm [512]bool
+
+func ParseAlgorithm(s string) (Algorithm, error) {
+	var a Algorithm
+	if len(s) != len(a) {
+		return a, fmt.Errorf("expected an algorithm of %v pixels, got %v", len(a), len(s))
+	}
+
+	for i, c := range s {
+		switch c {
+		case '#':
+			a[i] = true
+		case '.':
+		default:
+			return a, fmt.Errorf("unexpected pixel %q in algorithm", c)
+		}
+	}
+
+	return a, nil
+}
+
+// Image is an infinite image. Only a finite window of it can be anything
+// interesting, everything outside that window is the same Background pixel.
+//
+// That background is what makes the puzzle tricky: an algorithm that lights
+// up a pixel with nothing lit around it lights up the entire infinite
+// background in a single step, and if it also turns off a pixel with
+// everything lit around it then the background flashes back off on the
+// next. Keeping track of the background as its own pixel handles all of
+// that without ever having to grow the window by more than a pixel a step.
+//
+// Images are immutable, Enhance always returns a new one.
+type Image struct {
+	grid.Bounds
+	Background bool
+
+	// pixels holds the window inside Bounds, row by row.
+	pixels []bool
+}
+
+// At reports whether the pixel at p is lit, anywhere in the infinite image.
+func (img Image) At(p grid.Point) bool {
+	if !img.Contains(p) {
+		return img.Background
+	}
+
+	return img.pixels[(p.Y-img.Min.Y)*img.Width()+(p.X-img.Min.X)]
+}
+
+// Enhance runs the algorithm over the image once. The only pixels that can
+// end up different from the new background are the ones whose neighborhood
+// reaches into the current window, so the window grows by one pixel on
+// every side.
+func (img Image) Enhance(a Algorithm) Image {
+	out := Image{Bounds: img.Grow(1)}
+
+	points := out.Points()
+	out.pixels = make([]bool, len(points))
+	for i, p := range points {
+		out.pixels[i] = a[img.index(p)]
+	}
+
+	// The background is surrounded by nothing but more background, so
+	// it's either all zeros or all ones.
+	if img.Background {
+		out.Background = a[511]
+	} else {
+		out.Background = a[0]
+	}
+
+	return out
+}
+
+func (img Image) index(p grid.Point) int {
+	var idx int
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			idx <<= 1
+			if img.At(p.Add(grid.Point{X: dx, Y: dy})) {
+				idx |= 1
+			}
+		}
+	}
+
+	return idx
+}
+
+// EnhanceN runs the algorithm over the image n times.
+func (img Image) EnhanceN(a Algorithm, n int) Image {
+	for i := 0; i < n; i++ {
+		img = img.Enhance(a)
+	}
+
+	return img
+}
+
+// Lit counts the lit pixels in the image, which only works out to a number
+// when the background is dark.
+func (img Image) Lit() (int, error) {
+	if img.Background {
+		return 0, errors.New("infinitely many pixels are lit")
+	}
+
+	var count int
+	for _, pixel := range img.pixels {
+		if pixel {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// Render draws the window of the image along with margin pixels of the
+// background on every side of it.
+func (img Image) Render(margin int) string {
+	drawn := img.Grow(margin)
+
+	var b strings.Builder
+	for y := drawn.Min.Y; y <= drawn.Max.Y; y++ {
+		for x := drawn.Min.X; x <= drawn.Max.X; x++ {
+			if img.At(grid.Point{X: x, Y: y}) {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+func (img Image) String() string {
+	return img.Render(0)
+}
+
+// Parse reads the algorithm, a blank line and then the input image, which
+// sits with its top left corner at 0,0 on a dark background.
+func Parse(r io.Reader) (Algorithm, Image, error) {
+	scanner := bufio.NewScanner(r)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return Algorithm{}, Image{}, err
+		}
+
+		return Algorithm{}, Image{}, errors.New("missing image enhancement algorithm")
+	}
+
+	algorithm, err := ParseAlgorithm(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		return Algorithm{}, Image{}, err
+	}
+
+	var (
+		img   Image
+		width int
+	)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if width == 0 {
+			width = len(line)
+		}
+
+		if len(line) != width {
+			return Algorithm{}, Image{}, errors.New("every row of the image must be the same width")
+		}
+
+		for _, c := range line {
+			switch c {
+			case '#':
+				img.pixels = append(img.pixels, true)
+			case '.':
+				img.pixels = append(img.pixels, false)
+			default:
+				return Algorithm{}, Image{}, fmt.Errorf("unexpected pixel %q in image", c)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Algorithm{}, Image{}, err
+	}
+
+	if width == 0 {
+		return Algorithm{}, Image{}, errors.New("missing input image")
+	}
+
+	img.Max = grid.Point{X: width - 1, Y: len(img.pixels)/width - 1}
+
+	return algorithm, img, nil
+}
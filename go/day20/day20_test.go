@@ -0,0 +1,87 @@
+package day20
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stntngo/advent-2021/go/grid"
+)
+
+var testCase = `..#.#..#####.#.#.#.###.##.....###.##.#..###.####..#####..#....#..#..##..###..######.###...####..#..#####..##..#.#####...##.#.#..#.##..#.#......#.###.######.###.####...#.##.##..#..#..#####.....#.#....###..#.##......#.....#..#..#..##..#...##.######.####.####.#.#...#.......#..#.#.#...####.##.#......#..#...##.#.##..#...##.#.##..###.#......#.#.......#.#.#.####.###.##...#.....####.#..#..#.##.#....##..#.####....##...##..#...#......#.#.......#.......##..####..#...#.#.#...##..#.#..###..#####........#..####......#..#
+
+#..#.
+#....
+##..#
+..#..
+..###`
+
+func Test_Enhance(t *testing.T) {
+	algorithm, image, err := Parse(strings.NewReader(testCase))
+	require.NoError(t, err)
+
+	assert.Equal(t, "#..#.\n#....\n##..#\n..#..\n..###\n", image.String())
+
+	lit, err := image.Lit()
+	require.NoError(t, err)
+	assert.Equal(t, 10, lit)
+
+	once := image.Enhance(algorithm)
+	assert.Equal(t, `...............
+...............
+...............
+...............
+.....##.##.....
+....#..#.#.....
+....##.#..#....
+....####..#....
+.....#..##.....
+......##..#....
+.......#.#.....
+...............
+...............
+...............
+...............
+`, once.Render(4))
+
+	lit, err = image.EnhanceN(algorithm, 2).Lit()
+	require.NoError(t, err)
+	assert.Equal(t, 35, lit)
+
+	lit, err = image.EnhanceN(algorithm, 50).Lit()
+	require.NoError(t, err)
+	assert.Equal(t, 3351, lit)
+}
+
+func Test_FlippingBackground(t *testing.T) {
+	// The same example, but with an algorithm that lights up an empty
+	// neighborhood and turns off a full one, like the real inputs do.
+	flipped := "#" + testCase[1:511] + "." + testCase[512:]
+
+	algorithm, image, err := Parse(strings.NewReader(flipped))
+	require.NoError(t, err)
+
+	once := image.Enhance(algorithm)
+	assert.True(t, once.Background)
+	assert.True(t, once.At(grid.Point{X: -100, Y: 100}))
+
+	_, err = once.Lit()
+	assert.Error(t, err)
+
+	twice := once.Enhance(algorithm)
+	assert.False(t, twice.Background)
+	assert.Equal(t, twice.Width(), image.Width()+4)
+
+	_, err = twice.Lit()
+	assert.NoError(t, err)
+}
+
+func Test_Parse(t *testing.T) {
+	_, _, err := Parse(strings.NewReader("#.#\n\n#.\n.#"))
+	assert.Error(t, err)
+
+	_, _, err = Parse(strings.NewReader(testCase[:513] + "\n#.\n.#."))
+	assert.Error(t, err)
+}
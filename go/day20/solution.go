@@ -0,0 +1,113 @@
+package day20
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  20,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
+var (
+	_STEPS1 = registry.Param{
+		Name:    "steps1",
+		Usage:   "times the image is enhanced in part one",
+		Default: 2,
+		Check:   registry.AtLeast(0),
+	}
+
+	_STEPS2 = registry.Param{
+		Name:    "steps2",
+		Usage:   "times the image is enhanced in part two",
+		Default: 50,
+		Check:   registry.AtLeast(0),
+	}
+)
+
+type Solution struct {
+	algorithm Algorithm
+	image     Image
+	args      registry.Args
+}
+
+func (s *Solution) Name() string {
+	return "Trench Map"
+}
+
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
+func (s *Solution) Params() []registry.Param {
+	return []registry.Param{_STEPS1, _STEPS2}
+}
+
+func (s *Solution) SetArgs(args registry.Args) {
+	s.args = args
+}
+
+func (s *Solution) Load(r io.Reader) error {
+	algorithm, image, err := Parse(r)
+	if err != nil {
+		return err
+	}
+
+	s.algorithm = algorithm
+	s.image = image
+
+	return nil
+}
+
+func (s *Solution) lit(steps int) (string, error) {
+	if steps < 0 {
+		return "", errors.New("steps can't be negative")
+	}
+
+	lit, err := s.image.EnhanceN(s.algorithm, steps).Lit()
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(lit), nil
+}
+
+func (s *Solution) PartOne() (string, error) {
+	return s.lit(s.args.Int(_STEPS1))
+}
+
+func (s *Solution) PartTwo() (string, error) {
+	return s.lit(s.args.Int(_STEPS2))
+}
+
+func (s *Solution) Operations() []registry.Operation {
+	return []registry.Operation{
+		{
+			Name:  "Lit",
+			Args:  []string{"steps"},
+			Usage: "count the lit pixels after the given number of enhancements",
+			Call: func(args []int) (string, error) {
+				return s.lit(args[0])
+			},
+		},
+		{
+			Name:  "Render",
+			Args:  []string{"steps", "margin"},
+			Usage: "draw the image after the given number of enhancements, with margin pixels of background around it",
+			Call: func(args []int) (string, error) {
+				if args[0] < 0 || args[1] < 0 {
+					return "", errors.New("steps and margin can't be negative")
+				}
+
+				return "\n" + s.image.EnhanceN(s.algorithm, args[0]).Render(args[1]), nil
+			},
+		},
+	}
+}
@@ -0,0 +1,186 @@
+package day21
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// _TRACK is how many spaces there are around the game board.
+const _TRACK = 10
+
+type Player struct {
+	Position int
+	Score    int
+}
+
+// Move moves the player forward by spaces around the circular track and
+// scores them the space they land on.
+func (p Player) Move(spaces int) Player {
+	p.Position = (p.Position+spaces-1)%_TRACK + 1
+	p.Score += p.Position
+
+	return p
+}
+
+// DeterministicDie always rolls 1, then 2, then 3 and so on up to its number
+// of Sides before starting over at 1.
+type DeterministicDie struct {
+	Sides int
+	Rolls int
+}
+
+func (d *DeterministicDie) Roll() int {
+	roll := d.Rolls%d.Sides + 1
+	d.Rolls++
+
+	return roll
+}
+
+// Practice plays a game with the deterministic die until one of the players
+// reaches target, returning both players as they ended up along with how
+// many times the die was rolled.
+func Practice(one, two Player, target int) (Player, Player, int) {
+	die := &DeterministicDie{Sides: 100}
+
+	players := [2]Player{one, two}
+	for turn := 0; ; turn = 1 - turn {
+		players[turn] = players[turn].Move(die.Roll() + die.Roll() + die.Roll())
+		if players[turn].Score >= target {
+			return players[0], players[1], die.Rolls
+		}
+	}
+}
+
+// _OUTCOMES is how many of the 27 universes split off by three rolls of the
+// Dirac die end up moving the player each number of spaces, indexed by the
+// total rolled. Nobody can roll less than 3 so the first three are all 0.
+var _OUTCOMES = [10]uint64{3: 1, 4: 3, 5: 6, 6: 7, 7: 6, 8: 3, 9: 1}
+
+// State is everything there is to know about a game of Dirac Dice between
+// turns. Rather than tracking whose turn it is, the player whose turn it is
+// always goes first, which means a state and the same state with the players
+// swapped around only need counting once between them.
+type State struct {
+	Current, Waiting Player
+}
+
+// Wins counts the universes where each player wins, in the same order as
+// the players in the State they're counted from.
+type Wins [2]uint64
+
+// Dirac counts the universes each player wins in from every State of a game
+// to Target. There are only a few tens of thousands of distinct states for a
+// game to 21 but they're reached along trillions of different paths, so the
+// count for each is remembered the first time it's worked out.
+type Dirac struct {
+	Target int
+
+	wins map[State]Wins
+}
+
+func NewDirac(target int) *Dirac {
+	return &Dirac{
+		Target: target,
+		wins:   make(map[State]Wins),
+	}
+}
+
+// ErrOverflow is returned once the universes a player wins in can't be
+// counted in a uint64, which a game to 21 is nowhere near but a game to 28
+// or 29, depending on where the players start, gets past.
+var ErrOverflow = errors.New("universe count overflows a uint64")
+
+// tally adds the wins from each of some number of universes on to sum,
+// unless that can't be counted in a uint64.
+func tally(sum, universes, wins uint64) (uint64, error) {
+	hi, lo := bits.Mul64(universes, wins)
+	if hi != 0 {
+		return 0, ErrOverflow
+	}
+
+	total, carry := bits.Add64(sum, lo, 0)
+	if carry != 0 {
+		return 0, ErrOverflow
+	}
+
+	return total, nil
+}
+
+func (d *Dirac) Wins(s State) (wins Wins, err error) {
+	if count, ok := d.wins[s]; ok {
+		return count, nil
+	}
+
+	defer func() {
+		if err == nil {
+			d.wins[s] = wins
+		}
+	}()
+
+	for spaces, universes := range _OUTCOMES {
+		if universes == 0 {
+			continue
+		}
+
+		moved := s.Current.Move(spaces)
+		if moved.Score >= d.Target {
+			if wins[0], err = tally(wins[0], universes, 1); err != nil {
+				return Wins{}, err
+			}
+
+			continue
+		}
+
+		// It's the other player's turn next, so they're the one going
+		// first and the wins come back the other way around.
+		next, err := d.Wins(State{Current: s.Waiting, Waiting: moved})
+		if err != nil {
+			return Wins{}, err
+		}
+
+		if wins[0], err = tally(wins[0], universes, next[1]); err != nil {
+			return Wins{}, err
+		}
+
+		if wins[1], err = tally(wins[1], universes, next[0]); err != nil {
+			return Wins{}, err
+		}
+	}
+
+	return wins, nil
+}
+
+func ParsePlayers(r io.Reader) (Player, Player, error) {
+	scanner := bufio.NewScanner(r)
+
+	var players [2]Player
+	for i := range players {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return Player{}, Player{}, err
+			}
+
+			return Player{}, Player{}, errors.New("expected two players")
+		}
+
+		var n, position int
+		if _, err := fmt.Sscanf(scanner.Text(), "Player %d starting position: %d", &n, &position); err != nil {
+			return Player{}, Player{}, fmt.Errorf("invalid player %q: %w", scanner.Text(), err)
+		}
+
+		if n != i+1 {
+			return Player{}, Player{}, fmt.Errorf("expected player %v, got player %v", i+1, n)
+		}
+
+		if position < 1 || position > _TRACK {
+			return Player{}, Player{}, fmt.Errorf("player %v starts off the board at %v", n, position)
+		}
+
+		players[i].Position = position
+	}
+
+	return players[0], players[1], scanner.Err()
+}
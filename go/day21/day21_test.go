@@ -0,0 +1,60 @@
+package day21
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCase = `Player 1 starting position: 4
+Player 2 starting position: 8`
+
+func Test_Practice(t *testing.T) {
+	one, two, err := ParsePlayers(strings.NewReader(testCase))
+	require.NoError(t, err)
+	assert.Equal(t, Player{Position: 4}, one)
+	assert.Equal(t, Player{Position: 8}, two)
+
+	one, two, rolls := Practice(one, two, 1000)
+	assert.Equal(t, 1000, one.Score)
+	assert.Equal(t, 745, two.Score)
+	assert.Equal(t, 993, rolls)
+}
+
+func Test_Dirac(t *testing.T) {
+	var total uint64
+	for _, universes := range _OUTCOMES {
+		total += universes
+	}
+
+	assert.Equal(t, uint64(27), total)
+
+	wins, err := NewDirac(21).Wins(State{Current: Player{Position: 4}, Waiting: Player{Position: 8}})
+	require.NoError(t, err)
+	assert.Equal(t, Wins{444356092776315, 341960390180808}, wins)
+}
+
+func Test_DiracOverflow(t *testing.T) {
+	// The example's universes can be counted in a game to 27 but there
+	// are too many of them in a game to 28.
+	start := State{Current: Player{Position: 4}, Waiting: Player{Position: 8}}
+
+	_, err := NewDirac(27).Wins(start)
+	require.NoError(t, err)
+
+	_, err = NewDirac(28).Wins(start)
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+func Test_ParsePlayers(t *testing.T) {
+	_, _, err := ParsePlayers(strings.NewReader("Player 1 starting position: 4"))
+	assert.Error(t, err)
+
+	_, _, err = ParsePlayers(strings.NewReader("Player 1 starting position: 11\nPlayer 2 starting position: 8"))
+	assert.Error(t, err)
+
+	_, _, err = ParsePlayers(strings.NewReader("Player 2 starting position: 4\nPlayer 1 starting position: 8"))
+	assert.Error(t, err)
+}
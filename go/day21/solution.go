@@ -0,0 +1,101 @@
+package day21
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  21,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
+var (
+	_TARGET1 = registry.Param{
+		Name:    "target1",
+		Usage:   "score needed to win the practice game in part one",
+		Default: 1000,
+		Check:   registry.AtLeast(1),
+	}
+
+	_TARGET2 = registry.Param{
+		Name:    "target2",
+		Usage:   "score needed to win with the Dirac die in part two",
+		Default: 21,
+		Check:   registry.AtLeast(1),
+	}
+)
+
+type Solution struct {
+	one, two Player
+	args     registry.Args
+}
+
+func (s *Solution) Name() string {
+	return "Dirac Dice"
+}
+
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
+func (s *Solution) Params() []registry.Param {
+	return []registry.Param{_TARGET1, _TARGET2}
+}
+
+func (s *Solution) SetArgs(args registry.Args) {
+	s.args = args
+}
+
+func (s *Solution) Load(r io.Reader) error {
+	one, two, err := ParsePlayers(r)
+	if err != nil {
+		return err
+	}
+
+	s.one, s.two = one, two
+
+	return nil
+}
+
+func (s *Solution) PartOne() (string, error) {
+	target := s.args.Int(_TARGET1)
+	if target < 1 {
+		return "", errors.New("target1 must be at least 1")
+	}
+
+	one, two, rolls := Practice(s.one, s.two, target)
+
+	loser := one.Score
+	if two.Score < loser {
+		loser = two.Score
+	}
+
+	return strconv.Itoa(loser * rolls), nil
+}
+
+func (s *Solution) PartTwo() (string, error) {
+	target := s.args.Int(_TARGET2)
+	if target < 1 {
+		return "", errors.New("target2 must be at least 1")
+	}
+
+	wins, err := NewDirac(target).Wins(State{Current: s.one, Waiting: s.two})
+	if err != nil {
+		return "", err
+	}
+
+	most := wins[0]
+	if wins[1] > most {
+		most = wins[1]
+	}
+
+	return fmt.Sprintf("%v", most), nil
+}
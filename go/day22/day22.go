@@ -0,0 +1,196 @@
+package day22
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Cuboid is every cube from Min to Max along each axis, inclusive of both.
+type Cuboid struct {
+	MinX, MaxX int
+	MinY, MaxY int
+	MinZ, MaxZ int
+}
+
+// Cube returns the cuboid reaching radius cubes out from the origin in
+// every direction, like the -50..50 initialization region.
+func Cube(radius int) Cuboid {
+	return Cuboid{-radius, radius, -radius, radius, -radius, radius}
+}
+
+func (c Cuboid) Volume() int {
+	return (c.MaxX - c.MinX + 1) * (c.MaxY - c.MinY + 1) * (c.MaxZ - c.MinZ + 1)
+}
+
+// Intersect returns the cubes that are in both c and o, if there are any.
+func (c Cuboid) Intersect(o Cuboid) (Cuboid, bool) {
+	out := Cuboid{
+		MinX: max(c.MinX, o.MinX), MaxX: min(c.MaxX, o.MaxX),
+		MinY: max(c.MinY, o.MinY), MaxY: min(c.MaxY, o.MaxY),
+		MinZ: max(c.MinZ, o.MinZ), MaxZ: min(c.MaxZ, o.MaxZ),
+	}
+
+	if out.MinX > out.MaxX || out.MinY > out.MaxY || out.MinZ > out.MaxZ {
+		return Cuboid{}, false
+	}
+
+	return out, true
+}
+
+func (c Cuboid) String() string {
+	return fmt.Sprintf("x=%v..%v,y=%v..%v,z=%v..%v", c.MinX, c.MaxX, c.MinY, c.MaxY, c.MinZ, c.MaxZ)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+type Step struct {
+	On bool
+	Cuboid
+}
+
+// Reactor keeps track of which cubes are on without ever looking at a single
+// cube. Where day05 counts overlapping vents by tallying up every point of
+// every line, there are far too many cubes here for that, so the tally is
+// of whole cuboids instead and each one counts its volume times its weight
+// towards the cubes that are on.
+//
+// Turning a cuboid on adds it with a weight of one, but first every cuboid
+// already in the tally that it overlaps has its overlap added back in with
+// the opposite weight, so cubes that were already on aren't counted twice.
+// Turning a cuboid off is exactly the same, just without adding the cuboid
+// itself at the end. Plenty of the overlaps turn out to be the very same
+// cuboid, so keying the tally by cuboid cancels those out as it goes and
+// keeps it from growing anywhere near as fast as it otherwise would.
+type Reactor struct {
+	weights map[Cuboid]int
+}
+
+func NewReactor() *Reactor {
+	return &Reactor{weights: make(map[Cuboid]int)}
+}
+
+// Reboot runs every step, in order, on a brand new Reactor.
+func Reboot(steps []Step) *Reactor {
+	r := NewReactor()
+	for _, step := range steps {
+		r.Apply(step)
+	}
+
+	return r
+}
+
+func (r *Reactor) Apply(step Step) {
+	overlaps := make(map[Cuboid]int)
+	for cuboid, weight := range r.weights {
+		if overlap, ok := cuboid.Intersect(step.Cuboid); ok {
+			overlaps[overlap] -= weight
+		}
+	}
+
+	if step.On {
+		overlaps[step.Cuboid]++
+	}
+
+	for cuboid, weight := range overlaps {
+		r.weights[cuboid] += weight
+		if r.weights[cuboid] == 0 {
+			delete(r.weights, cuboid)
+		}
+	}
+}
+
+// On counts every cube that's on.
+func (r *Reactor) On() int {
+	var total int
+	for cuboid, weight := range r.weights {
+		total += weight * cuboid.Volume()
+	}
+
+	return total
+}
+
+// OnWithin counts the cubes that are on inside of region. The tally adds up
+// just the same when every cuboid in it is cut down to region first.
+func (r *Reactor) OnWithin(region Cuboid) int {
+	var total int
+	for cuboid, weight := range r.weights {
+		if overlap, ok := cuboid.Intersect(region); ok {
+			total += weight * overlap.Volume()
+		}
+	}
+
+	return total
+}
+
+func ParseStep(s string) (Step, error) {
+	var (
+		step  Step
+		state string
+	)
+
+	if _, err := fmt.Sscanf(
+		s,
+		"%s x=%d..%d,y=%d..%d,z=%d..%d",
+		&state,
+		&step.MinX, &step.MaxX,
+		&step.MinY, &step.MaxY,
+		&step.MinZ, &step.MaxZ,
+	); err != nil {
+		return Step{}, fmt.Errorf("invalid reboot step %q: %w", s, err)
+	}
+
+	switch state {
+	case "on":
+		step.On = true
+	case "off":
+	default:
+		return Step{}, fmt.Errorf("invalid reboot step %q: expected on or off", s)
+	}
+
+	if step.MinX > step.MaxX || step.MinY > step.MaxY || step.MinZ > step.MaxZ {
+		return Step{}, fmt.Errorf("invalid reboot step %q: empty cuboid", s)
+	}
+
+	return step, nil
+}
+
+func ParseSteps(r io.Reader) ([]Step, error) {
+	scanner := bufio.NewScanner(r)
+
+	var steps []Step
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		step, err := ParseStep(line)
+		if err != nil {
+			return nil, err
+		}
+
+		steps = append(steps, step)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return steps, nil
+}
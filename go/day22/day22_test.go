@@ -0,0 +1,108 @@
+package day22
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCase = `on x=10..12,y=10..12,z=10..12
+on x=11..13,y=11..13,z=11..13
+off x=9..11,y=9..11,z=9..11
+on x=10..10,y=10..10,z=10..10`
+
+func Test_Reboot(t *testing.T) {
+	steps, err := ParseSteps(strings.NewReader(testCase))
+	require.NoError(t, err)
+	require.Len(t, steps, 4)
+
+	reactor := NewReactor()
+	for i, expected := range []int{27, 46, 38, 39} {
+		reactor.Apply(steps[i])
+		assert.Equal(t, expected, reactor.On())
+	}
+
+	assert.Equal(t, 39, reactor.OnWithin(Cube(50)))
+	assert.Equal(t, 0, reactor.OnWithin(Cube(9)))
+	assert.Equal(t, 1, reactor.OnWithin(Cuboid{10, 10, 10, 10, 10, 10}))
+}
+
+// Test_RebootAgainstCubes checks the reactor against switching every single
+// cube on and off for reboots small enough to do that with.
+func Test_RebootAgainstCubes(t *testing.T) {
+	rng := rand.New(rand.NewSource(22))
+
+	type cube struct{ x, y, z int }
+
+	span := func() (int, int) {
+		a, b := rng.Intn(21)-10, rng.Intn(21)-10
+		if a > b {
+			a, b = b, a
+		}
+
+		return a, b
+	}
+
+	for trial := 0; trial < 20; trial++ {
+		on := make(map[cube]bool)
+		reactor := NewReactor()
+
+		for i := 0; i < 15; i++ {
+			var step Step
+			step.On = rng.Intn(3) > 0
+			step.MinX, step.MaxX = span()
+			step.MinY, step.MaxY = span()
+			step.MinZ, step.MaxZ = span()
+
+			reactor.Apply(step)
+
+			for x := step.MinX; x <= step.MaxX; x++ {
+				for y := step.MinY; y <= step.MaxY; y++ {
+					for z := step.MinZ; z <= step.MaxZ; z++ {
+						if step.On {
+							on[cube{x, y, z}] = true
+						} else {
+							delete(on, cube{x, y, z})
+						}
+					}
+				}
+			}
+		}
+
+		require.Equal(t, len(on), reactor.On())
+
+		var within int
+		for c := range on {
+			if c.x >= 0 && c.y >= 0 && c.z >= 0 {
+				within++
+			}
+		}
+
+		require.Equal(t, within, reactor.OnWithin(Cuboid{0, 10, 0, 10, 0, 10}))
+	}
+}
+
+func Test_Intersect(t *testing.T) {
+	overlap, ok := Cube(2).Intersect(Cuboid{1, 5, -5, 0, 2, 2})
+	require.True(t, ok)
+	assert.Equal(t, Cuboid{1, 2, -2, 0, 2, 2}, overlap)
+	assert.Equal(t, 6, overlap.Volume())
+
+	_, ok = Cube(2).Intersect(Cuboid{3, 5, 0, 0, 0, 0})
+	assert.False(t, ok)
+}
+
+func Test_ParseStep(t *testing.T) {
+	step, err := ParseStep("off x=-54112..-39298,y=-85059..-49293,z=-27449..7877")
+	require.NoError(t, err)
+	assert.Equal(t, Step{On: false, Cuboid: Cuboid{-54112, -39298, -85059, -49293, -27449, 7877}}, step)
+
+	_, err = ParseStep("toggle x=1..2,y=1..2,z=1..2")
+	assert.Error(t, err)
+
+	_, err = ParseStep("on x=2..1,y=1..2,z=1..2")
+	assert.Error(t, err)
+}
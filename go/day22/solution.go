@@ -0,0 +1,89 @@
+package day22
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  22,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
+var _RADIUS = registry.Param{
+	Name:    "radius",
+	Usage:   "how far the initialization region reaches from the origin in part one",
+	Default: 50,
+	Check:   registry.AtLeast(0),
+}
+
+type Solution struct {
+	reactor *Reactor
+	args    registry.Args
+}
+
+func (s *Solution) Name() string {
+	return "Reactor Reboot"
+}
+
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
+func (s *Solution) Params() []registry.Param {
+	return []registry.Param{_RADIUS}
+}
+
+func (s *Solution) SetArgs(args registry.Args) {
+	s.args = args
+}
+
+// Load runs the whole reboot up front. Counting the cubes that are on, in
+// the initialization region or anywhere else, is cheap by comparison.
+func (s *Solution) Load(r io.Reader) error {
+	steps, err := ParseSteps(r)
+	if err != nil {
+		return err
+	}
+
+	s.reactor = Reboot(steps)
+
+	return nil
+}
+
+func (s *Solution) PartOne() (string, error) {
+	radius := s.args.Int(_RADIUS)
+	if radius < 0 {
+		return "", errors.New("radius can't be negative")
+	}
+
+	return strconv.Itoa(s.reactor.OnWithin(Cube(radius))), nil
+}
+
+func (s *Solution) PartTwo() (string, error) {
+	return strconv.Itoa(s.reactor.On()), nil
+}
+
+func (s *Solution) Operations() []registry.Operation {
+	return []registry.Operation{
+		{
+			Name:  "Count",
+			Args:  []string{"x0", "x1", "y0", "y1", "z0", "z1"},
+			Usage: "count the cubes that are on in the region x0..x1,y0..y1,z0..z1",
+			Call: func(args []int) (string, error) {
+				region := Cuboid{args[0], args[1], args[2], args[3], args[4], args[5]}
+				if region.MinX > region.MaxX || region.MinY > region.MaxY || region.MinZ > region.MaxZ {
+					return "", errors.New("each minimum must be no more than its maximum")
+				}
+
+				return strconv.Itoa(s.reactor.OnWithin(region)), nil
+			},
+		},
+	}
+}
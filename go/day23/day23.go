@@ -0,0 +1,488 @@
+package day23
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/stntngo/advent-2021/go/day15"
+)
+
+// Amphipod is the type of amphipod in a space of the burrow, if there's
+// any amphipod there at all.
+type Amphipod byte
+
+const (
+	Empty Amphipod = iota
+	Amber
+	Bronze
+	Copper
+	Desert
+)
+
+func (a Amphipod) Energy() int {
+	return [...]int{0, 1, 10, 100, 1000}[a]
+}
+
+// Room is the index of the side room the amphipod belongs in.
+func (a Amphipod) Room() int {
+	return int(a) - 1
+}
+
+func (a Amphipod) String() string {
+	return string(".ABCD"[a])
+}
+
+func ParseAmphipod(c byte) (Amphipod, error) {
+	switch c {
+	case '.':
+		return Empty, nil
+	case 'A':
+		return Amber, nil
+	case 'B':
+		return Bronze, nil
+	case 'C':
+		return Copper, nil
+	case 'D':
+		return Desert, nil
+	}
+
+	return Empty, fmt.Errorf("unexpected amphipod %q", c)
+}
+
+const (
+	_HALLWAY = 11
+	_ROOMS   = 4
+	_DEPTH   = 4
+)
+
+// door is the space in the hallway right outside a side room, which no
+// amphipod is ever allowed to stop in.
+func door(room int) int {
+	return 2 + 2*room
+}
+
+func isDoor(x int) bool {
+	return x >= door(0) && x <= door(_ROOMS-1) && x%2 == 0
+}
+
+// Burrow is one arrangement of the amphipods. Its rooms are always four
+// spaces deep to leave room for the unfolded diagram but only the top Depth
+// spaces of each are ever used, with the first of them right by the door.
+//
+// Burrow is a day15.Node, generating the moves out of it as its Edges only
+// when asked. There are far too many arrangements to build the whole graph
+// up front, but the search only ever visits a small fraction of them.
+type Burrow struct {
+	Depth   int
+	Hallway [_HALLWAY]Amphipod
+	Rooms   [_ROOMS][_DEPTH]Amphipod
+}
+
+// ID numbers each arrangement by reading every space of the burrow as a
+// digit in base 5, which only just fits in an int for the unfolded burrow.
+// The search only ever compares burrows of the same depth so Depth can be
+// left out of it.
+func (b Burrow) ID() day15.ID {
+	var id int
+	for _, a := range b.Hallway {
+		id = id*5 + int(a)
+	}
+
+	for _, room := range b.Rooms {
+		for _, a := range room {
+			id = id*5 + int(a)
+		}
+	}
+
+	return day15.ID(id)
+}
+
+// Decode is the reverse of ID, turning it back into the burrow of the given
+// depth it was worked out from.
+func Decode(id day15.ID, depth int) Burrow {
+	b := Burrow{Depth: depth}
+
+	n := int(id)
+	for r := _ROOMS - 1; r >= 0; r-- {
+		for d := _DEPTH - 1; d >= 0; d-- {
+			b.Rooms[r][d] = Amphipod(n % 5)
+			n /= 5
+		}
+	}
+
+	for x := _HALLWAY - 1; x >= 0; x-- {
+		b.Hallway[x] = Amphipod(n % 5)
+		n /= 5
+	}
+
+	return b
+}
+
+// Organized returns the burrow of the same depth with every amphipod in its
+// own room, which is where the search is headed.
+func (b Burrow) Organized() Burrow {
+	out := Burrow{Depth: b.Depth}
+	for r := range out.Rooms {
+		for d := 0; d < b.Depth; d++ {
+			out.Rooms[r][d] = Amphipod(r + 1)
+		}
+	}
+
+	return out
+}
+
+// Unfold returns the burrow with the two lines of the diagram that were
+// folded away put back in between the top and bottom of every room.
+func (b Burrow) Unfold() Burrow {
+	out := b
+	out.Depth = b.Depth + 2
+
+	folded := [2][_ROOMS]Amphipod{
+		{Desert, Copper, Bronze, Amber},
+		{Desert, Bronze, Amber, Copper},
+	}
+
+	for r := range out.Rooms {
+		out.Rooms[r][1] = folded[0][r]
+		out.Rooms[r][2] = folded[1][r]
+		out.Rooms[r][3] = b.Rooms[r][1]
+	}
+
+	return out
+}
+
+// settled reports whether every amphipod in the room from depth d down is
+// already in the room it belongs in, so none of them ever needs to move.
+func (b Burrow) settled(room, d int) bool {
+	for ; d < b.Depth; d++ {
+		if a := b.Rooms[room][d]; a != Empty && a.Room() != room {
+			return false
+		}
+	}
+
+	return true
+}
+
+// clear reports whether the hallway is empty from x to y, not counting x.
+func (b Burrow) clear(x, y int) bool {
+	step := 1
+	if y < x {
+		step = -1
+	}
+
+	for x != y {
+		x += step
+		if b.Hallway[x] != Empty {
+			return false
+		}
+	}
+
+	return true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// Edges are all the moves the rules allow out of the burrow. An amphipod
+// either leaves its room for somewhere in the hallway or leaves the hallway
+// for its own room, never anything else. Going straight from one room to
+// another is the same as stopping off somewhere in the hallway along the
+// way, so there's nothing to gain from treating it as a move of its own.
+func (b Burrow) Edges() []day15.Edge {
+	var edges []day15.Edge
+
+	for x, a := range b.Hallway {
+		if a == Empty {
+			continue
+		}
+
+		room := a.Room()
+		if !b.settled(room, 0) || !b.clear(x, door(room)) {
+			continue
+		}
+
+		d := 0
+		for d+1 < b.Depth && b.Rooms[room][d+1] == Empty {
+			d++
+		}
+
+		to := b
+		to.Hallway[x] = Empty
+		to.Rooms[room][d] = a
+
+		edges = append(edges, Move{
+			Amphipod: a,
+			from:     b,
+			to:       to,
+			Steps:    abs(x-door(room)) + d + 1,
+		})
+	}
+
+	for room := range b.Rooms {
+		d := 0
+		for d < b.Depth && b.Rooms[room][d] == Empty {
+			d++
+		}
+
+		if d == b.Depth || b.settled(room, d) {
+			continue
+		}
+
+		a := b.Rooms[room][d]
+		for x := range b.Hallway {
+			if isDoor(x) || !b.clear(door(room), x) || b.Hallway[x] != Empty {
+				continue
+			}
+
+			to := b
+			to.Rooms[room][d] = Empty
+			to.Hallway[x] = a
+
+			edges = append(edges, Move{
+				Amphipod: a,
+				from:     b,
+				to:       to,
+				Steps:    d + 1 + abs(x-door(room)),
+			})
+		}
+	}
+
+	return edges
+}
+
+// MinimumEnergy is a lower bound on the energy it'll take to organize the
+// burrow, which makes it an admissible heuristic for the search. Every
+// amphipod that isn't settled has to at least get out of wherever it is,
+// walk over to the door of its room and step inside. Then however many of
+// them have to go into each room, they can't all stop at the top: filling a
+// room that needs n more amphipods takes another 0+1+...+(n-1) steps.
+func (b Burrow) MinimumEnergy() int {
+	var (
+		energy   int
+		entering [_ROOMS]int
+	)
+
+	for x, a := range b.Hallway {
+		if a == Empty {
+			continue
+		}
+
+		energy += a.Energy() * (abs(x-door(a.Room())) + 1)
+		entering[a.Room()]++
+	}
+
+	for room := range b.Rooms {
+		for d := 0; d < b.Depth; d++ {
+			a := b.Rooms[room][d]
+			if a == Empty || b.settled(room, d) {
+				continue
+			}
+
+			// An amphipod already in its own room but in the way of
+			// one that isn't has to step out and back in at least.
+			steps := abs(door(room) - door(a.Room()))
+			if steps == 0 {
+				steps = 2
+			}
+
+			energy += a.Energy() * (d + 1 + steps + 1)
+			entering[a.Room()]++
+		}
+	}
+
+	for room, n := range entering {
+		energy += Amphipod(room+1).Energy() * n * (n - 1) / 2
+	}
+
+	return energy
+}
+
+// String draws the burrow the same way the puzzle does.
+func (b Burrow) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("#############\n#")
+	for _, a := range b.Hallway {
+		sb.WriteString(a.String())
+	}
+
+	sb.WriteString("#\n")
+
+	for d := 0; d < b.Depth; d++ {
+		if d == 0 {
+			sb.WriteString("###")
+		} else {
+			sb.WriteString("  #")
+		}
+
+		for room := range b.Rooms {
+			sb.WriteString(b.Rooms[room][d].String())
+			sb.WriteByte('#')
+		}
+
+		if d == 0 {
+			sb.WriteString("##")
+		}
+
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString("  #########\n")
+
+	return sb.String()
+}
+
+// Move is a single amphipod walking from one space in the burrow to another,
+// an edge of the graph of burrows weighed by the energy it takes.
+type Move struct {
+	Amphipod Amphipod
+	Steps    int
+
+	from, to Burrow
+}
+
+func (m Move) From() day15.Node {
+	return m.from
+}
+
+func (m Move) To() day15.Node {
+	return m.to
+}
+
+func (m Move) Weight() int {
+	return m.Steps * m.Amphipod.Energy()
+}
+
+func (m Move) String() string {
+	return fmt.Sprintf("%v moves %v spaces for %v energy", m.Amphipod, m.Steps, m.Weight())
+}
+
+// Organize finds the moves that organize the burrow with the least energy.
+func Organize(b Burrow) ([]Move, int, error) {
+	return OrganizeContext(context.Background(), b)
+}
+
+// OrganizeContext is Organize, giving up on the search if ctx is cancelled.
+func OrganizeContext(ctx context.Context, b Burrow) ([]Move, int, error) {
+	end := b.Organized()
+
+	path, err := day15.FindPathContext(ctx, b, end, func(n day15.Node) int {
+		return n.(Burrow).MinimumEnergy()
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// FindPath only hands back the IDs of the burrows along the way, in
+	// reverse and without the one it started from, so the moves have to
+	// be pieced back together from them.
+	var (
+		moves  []Move
+		energy int
+	)
+
+	cur := b
+	for i := len(path) - 1; i >= 0; i-- {
+		found := false
+		for _, edge := range cur.Edges() {
+			if edge.To().ID() != path[i] {
+				continue
+			}
+
+			move := edge.(Move)
+			moves = append(moves, move)
+			energy += move.Weight()
+			cur = move.to
+			found = true
+
+			break
+		}
+
+		if !found {
+			return nil, 0, errors.New("search returned a path with an impossible move")
+		}
+	}
+
+	return moves, energy, nil
+}
+
+// ParseBurrow reads the diagram of the burrow, however deep its rooms are.
+func ParseBurrow(r io.Reader) (Burrow, error) {
+	scanner := bufio.NewScanner(r)
+
+	var lines []string
+	for scanner.Scan() {
+		if line := strings.TrimRight(scanner.Text(), " \r"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Burrow{}, err
+	}
+
+	// The wall above, the hallway, at least one row of the rooms and the
+	// wall below.
+	if len(lines) < 4 || len(lines) > 3+_DEPTH {
+		return Burrow{}, errors.New("unexpected burrow diagram")
+	}
+
+	var b Burrow
+	if len(lines[1]) != _HALLWAY+2 {
+		return Burrow{}, errors.New("unexpected hallway in burrow diagram")
+	}
+
+	for x := range b.Hallway {
+		a, err := ParseAmphipod(lines[1][x+1])
+		if err != nil {
+			return Burrow{}, err
+		}
+
+		b.Hallway[x] = a
+	}
+
+	b.Depth = len(lines) - 3
+	for d, line := range lines[2 : len(lines)-1] {
+		for room := range b.Rooms {
+			x := door(room) + 1
+			if x >= len(line) {
+				return Burrow{}, fmt.Errorf("room row %v is too short", d+1)
+			}
+
+			a, err := ParseAmphipod(line[x])
+			if err != nil {
+				return Burrow{}, err
+			}
+
+			b.Rooms[room][d] = a
+		}
+	}
+
+	var counts [_ROOMS + 1]int
+	for _, a := range b.Hallway {
+		counts[a]++
+	}
+
+	for _, room := range b.Rooms {
+		for _, a := range room[:b.Depth] {
+			counts[a]++
+		}
+	}
+
+	for a := Amber; a <= Desert; a++ {
+		if counts[a] != b.Depth {
+			return Burrow{}, fmt.Errorf("expected %v amphipods of type %v, found %v", b.Depth, a, counts[a])
+		}
+	}
+
+	return b, nil
+}
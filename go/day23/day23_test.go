@@ -0,0 +1,83 @@
+package day23
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCase = `#############
+#...........#
+###B#C#B#D###
+  #A#D#C#A#
+  #########`
+
+func Test_Organize(t *testing.T) {
+	burrow, err := ParseBurrow(strings.NewReader(testCase))
+	require.NoError(t, err)
+	assert.Equal(t, testCase+"\n", burrow.String())
+
+	moves, energy, err := Organize(burrow)
+	require.NoError(t, err)
+	assert.Equal(t, 12521, energy)
+	var total int
+	for _, move := range moves {
+		total += move.Weight()
+	}
+
+	assert.Equal(t, energy, total)
+
+	unfolded := burrow.Unfold()
+	assert.Equal(t, `#############
+#...........#
+###B#C#B#D###
+  #D#C#B#A#
+  #D#B#A#C#
+  #A#D#C#A#
+  #########
+`, unfolded.String())
+
+	_, energy, err = Organize(unfolded)
+	require.NoError(t, err)
+	assert.Equal(t, 44169, energy)
+}
+
+func Test_ID(t *testing.T) {
+	burrow, err := ParseBurrow(strings.NewReader(testCase))
+	require.NoError(t, err)
+
+	unfolded := burrow.Unfold()
+	assert.Equal(t, burrow, Decode(burrow.ID(), burrow.Depth))
+	assert.Equal(t, unfolded, Decode(unfolded.ID(), unfolded.Depth))
+	assert.Equal(t, unfolded.Organized(), Decode(unfolded.Organized().ID(), unfolded.Depth))
+}
+
+func Test_MinimumEnergy(t *testing.T) {
+	burrow, err := ParseBurrow(strings.NewReader(testCase))
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, burrow.Organized().MinimumEnergy())
+
+	// The heuristic can never overestimate, or the search could settle on
+	// a path that isn't actually the cheapest.
+	assert.LessOrEqual(t, burrow.MinimumEnergy(), 12521)
+	assert.LessOrEqual(t, burrow.Unfold().MinimumEnergy(), 44169)
+
+	moves, energy, err := Organize(burrow)
+	require.NoError(t, err)
+
+	for _, move := range moves {
+		energy -= move.Weight()
+		assert.LessOrEqual(t, move.to.MinimumEnergy(), energy)
+	}
+}
+
+func Test_ParseBurrow(t *testing.T) {
+	_, err := ParseBurrow(strings.NewReader(strings.Replace(testCase, "A#D", "A#A", 1)))
+	assert.Error(t, err)
+
+	_, err = ParseBurrow(strings.NewReader("#############\n#...........#\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,118 @@
+package day23
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  23,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
+type Solution struct {
+	burrow Burrow
+}
+
+func (s *Solution) Name() string {
+	return "Amphipod"
+}
+
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
+func (s *Solution) Load(r io.Reader) error {
+	burrow, err := ParseBurrow(r)
+	if err != nil {
+		return err
+	}
+
+	if burrow.Depth != 2 {
+		return fmt.Errorf("expected the folded diagram with rooms 2 deep, got %v", burrow.Depth)
+	}
+
+	s.burrow = burrow
+
+	return nil
+}
+
+// burrowFor is the burrow to organize in the given part of the puzzle.
+func (s *Solution) burrowFor(part int) Burrow {
+	if part == 2 {
+		return s.burrow.Unfold()
+	}
+
+	return s.burrow
+}
+
+func (s *Solution) energy(ctx context.Context, part int) (string, error) {
+	_, energy, err := OrganizeContext(ctx, s.burrowFor(part))
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(energy), nil
+}
+
+func (s *Solution) LoadContext(_ context.Context, r io.Reader) error {
+	return s.Load(r)
+}
+
+func (s *Solution) PartOne() (string, error) {
+	return s.PartOneContext(context.Background())
+}
+
+func (s *Solution) PartOneContext(ctx context.Context) (string, error) {
+	return s.energy(ctx, 1)
+}
+
+func (s *Solution) PartTwo() (string, error) {
+	return s.PartTwoContext(context.Background())
+}
+
+func (s *Solution) PartTwoContext(ctx context.Context) (string, error) {
+	return s.energy(ctx, 2)
+}
+
+func (s *Solution) Operations() []registry.Operation {
+	return []registry.Operation{
+		{
+			Name:  "Moves",
+			Args:  []string{"part"},
+			Usage: "draw each move of the cheapest way to organize the burrow in part 1 or 2",
+			Call: func(args []int) (string, error) {
+				if args[0] != 1 && args[0] != 2 {
+					return "", fmt.Errorf("part must be 1 or 2, got %v", args[0])
+				}
+
+				burrow := s.burrowFor(args[0])
+
+				moves, energy, err := Organize(burrow)
+				if err != nil {
+					return "", err
+				}
+
+				var b strings.Builder
+				b.WriteString("\n")
+				b.WriteString(burrow.String())
+
+				for _, move := range moves {
+					fmt.Fprintf(&b, "\n%v\n%v", move, move.to)
+				}
+
+				fmt.Fprintf(&b, "\n%v moves for %v energy", len(moves), energy)
+
+				return b.String(), nil
+			},
+		},
+	}
+}
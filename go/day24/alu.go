@@ -0,0 +1,236 @@
+package day24
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type Register int
+
+const (
+	W Register = iota
+	X
+	Y
+	Z
+)
+
+func (r Register) String() string {
+	return string("wxyz"[r])
+}
+
+func ParseRegister(s string) (Register, bool) {
+	if len(s) != 1 || s[0] < 'w' || s[0] > 'z' {
+		return 0, false
+	}
+
+	return Register(s[0] - 'w'), true
+}
+
+type Op int
+
+const (
+	Inp Op = iota
+	Add
+	Mul
+	Div
+	Mod
+	Eql
+)
+
+var _OPS = [...]string{"inp", "add", "mul", "div", "mod", "eql"}
+
+func (o Op) String() string {
+	return _OPS[o]
+}
+
+func ParseOp(s string) (Op, bool) {
+	for i, name := range _OPS {
+		if name == s {
+			return Op(i), true
+		}
+	}
+
+	return 0, false
+}
+
+// Operand is the second argument of an instruction, either a register or a
+// number given right there in the instruction.
+type Operand struct {
+	Register  Register
+	Value     int
+	Immediate bool
+}
+
+func (o Operand) String() string {
+	if o.Immediate {
+		return strconv.Itoa(o.Value)
+	}
+
+	return o.Register.String()
+}
+
+type Instruction struct {
+	Op Op
+	A  Register
+
+	// B is unused by inp, which only takes the one argument.
+	B Operand
+}
+
+func (i Instruction) String() string {
+	if i.Op == Inp {
+		return fmt.Sprintf("%v %v", i.Op, i.A)
+	}
+
+	return fmt.Sprintf("%v %v %v", i.Op, i.A, i.B)
+}
+
+func ParseInstruction(s string) (Instruction, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return Instruction{}, errors.New("empty instruction")
+	}
+
+	op, ok := ParseOp(fields[0])
+	if !ok {
+		return Instruction{}, fmt.Errorf("unknown instruction %q", s)
+	}
+
+	args := 3
+	if op == Inp {
+		args = 2
+	}
+
+	if len(fields) != args {
+		return Instruction{}, fmt.Errorf("%v takes %v arguments: %q", op, args-1, s)
+	}
+
+	a, ok := ParseRegister(fields[1])
+	if !ok {
+		return Instruction{}, fmt.Errorf("invalid register %q: %q", fields[1], s)
+	}
+
+	inst := Instruction{Op: op, A: a}
+	if op == Inp {
+		return inst, nil
+	}
+
+	if b, ok := ParseRegister(fields[2]); ok {
+		inst.B = Operand{Register: b}
+		return inst, nil
+	}
+
+	value, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return Instruction{}, fmt.Errorf("invalid operand %q: %q", fields[2], s)
+	}
+
+	inst.B = Operand{Value: value, Immediate: true}
+
+	return inst, nil
+}
+
+type Program []Instruction
+
+func ParseProgram(r io.Reader) (Program, error) {
+	scanner := bufio.NewScanner(r)
+
+	var program Program
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		inst, err := ParseInstruction(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %w", len(program)+1, err)
+		}
+
+		program = append(program, inst)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return program, nil
+}
+
+// Registers holds the values of w, x, y and z, in that order.
+type Registers [4]int
+
+func (r Registers) String() string {
+	return fmt.Sprintf("w=%v x=%v y=%v z=%v", r[W], r[X], r[Y], r[Z])
+}
+
+// ALU runs a Program one instruction at a time, reading each inp from the
+// front of its Input.
+type ALU struct {
+	Registers Registers
+	Input     []int
+}
+
+// Exec runs a single instruction. The puzzle says the ALU crashes on a few
+// of them, which here means returning an error and leaving the registers
+// just as they were.
+func (alu *ALU) Exec(inst Instruction) error {
+	a := alu.Registers[inst.A]
+
+	b := inst.B.Value
+	if !inst.B.Immediate {
+		b = alu.Registers[inst.B.Register]
+	}
+
+	switch inst.Op {
+	case Inp:
+		if len(alu.Input) == 0 {
+			return fmt.Errorf("%v: out of input", inst)
+		}
+
+		a, alu.Input = alu.Input[0], alu.Input[1:]
+	case Add:
+		a += b
+	case Mul:
+		a *= b
+	case Div:
+		if b == 0 {
+			return fmt.Errorf("%v: division by zero", inst)
+		}
+
+		a /= b
+	case Mod:
+		if a < 0 || b <= 0 {
+			return fmt.Errorf("%v: %v mod %v", inst, a, b)
+		}
+
+		a %= b
+	case Eql:
+		if a == b {
+			a = 1
+		} else {
+			a = 0
+		}
+	}
+
+	alu.Registers[inst.A] = a
+
+	return nil
+}
+
+// Run runs the whole program against input, from registers that all start
+// out at zero.
+func Run(p Program, input []int) (Registers, error) {
+	alu := ALU{Input: input}
+	for n, inst := range p {
+		if err := alu.Exec(inst); err != nil {
+			return alu.Registers, fmt.Errorf("instruction %v: %w", n+1, err)
+		}
+	}
+
+	return alu.Registers, nil
+}
@@ -0,0 +1,81 @@
+package day24
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var binary = `inp w
+add z w
+mod z 2
+div w 2
+add y w
+mod y 2
+div w 2
+add x w
+mod x 2
+div w 2
+mod w 2`
+
+func Test_Run(t *testing.T) {
+	negate, err := ParseProgram(strings.NewReader("inp x\nmul x -1"))
+	require.NoError(t, err)
+
+	registers, err := Run(negate, []int{7})
+	require.NoError(t, err)
+	assert.Equal(t, -7, registers[X])
+
+	triple, err := ParseProgram(strings.NewReader("inp z\ninp x\nmul z 3\neql z x"))
+	require.NoError(t, err)
+
+	registers, err = Run(triple, []int{3, 9})
+	require.NoError(t, err)
+	assert.Equal(t, 1, registers[Z])
+
+	registers, err = Run(triple, []int{3, 8})
+	require.NoError(t, err)
+	assert.Equal(t, 0, registers[Z])
+
+	program, err := ParseProgram(strings.NewReader(binary))
+	require.NoError(t, err)
+
+	registers, err = Run(program, []int{13})
+	require.NoError(t, err)
+	assert.Equal(t, Registers{1, 1, 0, 1}, registers)
+	assert.Equal(t, "w=1 x=1 y=0 z=1", registers.String())
+}
+
+func Test_Crash(t *testing.T) {
+	for _, source := range []string{
+		"inp w\ninp x",
+		"inp w\ndiv w x",
+		"inp w\nmod w 0",
+		"inp w\nmul w -1\nmod w 3",
+	} {
+		program, err := ParseProgram(strings.NewReader(source))
+		require.NoError(t, err)
+
+		_, err = Run(program, []int{4})
+		assert.Error(t, err, source)
+	}
+}
+
+func Test_ParseProgram(t *testing.T) {
+	program, err := ParseProgram(strings.NewReader(binary))
+	require.NoError(t, err)
+
+	var lines []string
+	for _, inst := range program {
+		lines = append(lines, inst.String())
+	}
+
+	assert.Equal(t, binary, strings.Join(lines, "\n"))
+
+	for _, source := range []string{"jmp w 1", "add w", "inp v", "add w one", "inp w x"} {
+		_, err := ParseProgram(strings.NewReader(source))
+		assert.Error(t, err, source)
+	}
+}
@@ -0,0 +1,195 @@
+package day24
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// _DIGITS is how many digits long a model number is.
+const _DIGITS = 14
+
+// _BLOCK is the part of MONAD that checks a single digit, with a %d in
+// place of each of the three numbers that differ from one digit to the
+// next. Every copy of MONAD is the same fourteen blocks one after another,
+// only with different numbers.
+const _BLOCK = `inp w
+mul x 0
+add x z
+mod x 26
+div z %d
+add x %d
+eql x w
+eql x 0
+mul y 0
+add y 25
+mul y x
+add y 1
+mul z y
+mul y 0
+add y w
+add y %d
+mul y x
+add z y`
+
+// Block holds the numbers in one of the blocks of MONAD. Between them, the
+// blocks treat z as a stack of base 26 digits. A block that Divides by 1
+// always pushes the digit it reads plus Offset onto the stack. A block that
+// Divides by 26 pops the top of the stack off, and unless the digit it reads
+// is whatever it popped plus Check, pushes the digit plus Offset back on.
+//
+// z only ends up zero when the stack ends up empty, so every one of those
+// pops has to match. Each pop then ties the digit it reads to the digit
+// pushed by the block it pops, which is all there is to the model numbers.
+type Block struct {
+	Divide, Check, Offset int
+}
+
+// Blocks pulls the numbers out of each of the blocks of program, checking
+// that the rest of the program is exactly what the analysis relies on it
+// being along the way.
+func Blocks(program Program) ([]Block, error) {
+	template := strings.Split(_BLOCK, "\n")
+	if len(program) != _DIGITS*len(template) {
+		return nil, fmt.Errorf("expected %v instructions, got %v", _DIGITS*len(template), len(program))
+	}
+
+	blocks := make([]Block, _DIGITS)
+	for i := range blocks {
+		var (
+			b      = &blocks[i]
+			params = []*int{&b.Divide, &b.Check, &b.Offset}
+		)
+
+		for j, line := range template {
+			inst := program[i*len(template)+j]
+			if !strings.Contains(line, "%d") {
+				if inst.String() != line {
+					return nil, fmt.Errorf("block %v: expected %q, got %q", i+1, line, inst)
+				}
+
+				continue
+			}
+
+			if _, err := fmt.Sscanf(inst.String(), line, params[0]); err != nil {
+				return nil, fmt.Errorf("block %v: expected %q, got %q", i+1, line, inst)
+			}
+
+			params = params[1:]
+		}
+
+		switch {
+		case b.Divide == 1 && b.Check < 10:
+			// A digit could match, so the block wouldn't always push.
+			return nil, fmt.Errorf("block %v: pushes conditionally", i+1)
+		case b.Divide != 1 && b.Divide != 26:
+			return nil, fmt.Errorf("block %v: divides z by %v", i+1, b.Divide)
+		}
+	}
+
+	return blocks, nil
+}
+
+// Constraint says the digit at index J has to be the digit at index I plus
+// Diff for the model number to be valid.
+type Constraint struct {
+	I, J, Diff int
+}
+
+// Constraints pairs up the blocks that push with the blocks that pop them.
+func Constraints(blocks []Block) ([]Constraint, error) {
+	var (
+		stack       []int
+		constraints []Constraint
+	)
+
+	for j, b := range blocks {
+		if b.Divide == 1 {
+			stack = append(stack, j)
+			continue
+		}
+
+		if len(stack) == 0 {
+			return nil, fmt.Errorf("block %v pops from an empty stack", j+1)
+		}
+
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		diff := blocks[i].Offset + b.Check
+		if diff <= -9 || diff >= 9 {
+			return nil, fmt.Errorf("blocks %v and %v can never match", i+1, j+1)
+		}
+
+		constraints = append(constraints, Constraint{I: i, J: j, Diff: diff})
+	}
+
+	if len(stack) > 0 {
+		return nil, errors.New("z never gets back down to zero")
+	}
+
+	return constraints, nil
+}
+
+// ModelNumbers works out the largest and smallest model numbers MONAD
+// accepts straight from its constraints. Each constraint only involves its
+// own two digits, so they can each be pushed as high, or as low, as they'll
+// go without getting in the way of any of the others.
+func ModelNumbers(program Program) (largest, smallest []int, err error) {
+	blocks, err := Blocks(program)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	constraints, err := Constraints(blocks)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	largest = make([]int, _DIGITS)
+	smallest = make([]int, _DIGITS)
+
+	for _, c := range constraints {
+		if c.Diff >= 0 {
+			largest[c.I], largest[c.J] = 9-c.Diff, 9
+			smallest[c.I], smallest[c.J] = 1, 1+c.Diff
+		} else {
+			largest[c.I], largest[c.J] = 9, 9+c.Diff
+			smallest[c.I], smallest[c.J] = 1-c.Diff, 1
+		}
+	}
+
+	// Nothing about the analysis is worth trusting more than actually
+	// running the program.
+	for _, digits := range [][]int{largest, smallest} {
+		if err := Validate(program, digits); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return largest, smallest, nil
+}
+
+// Validate runs MONAD against the digits of a model number, which is valid
+// when it leaves z at zero.
+func Validate(program Program, digits []int) error {
+	registers, err := Run(program, digits)
+	if err != nil {
+		return err
+	}
+
+	if registers[Z] != 0 {
+		return fmt.Errorf("%v is not a valid model number: z is %v", Format(digits), registers[Z])
+	}
+
+	return nil
+}
+
+func Format(digits []int) string {
+	var b strings.Builder
+	for _, d := range digits {
+		b.WriteByte(byte('0' + d))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,124 @@
+package day24
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// monad writes out a copy of MONAD with the given numbers in each block.
+func monad(blocks []Block) string {
+	var out []string
+	for _, b := range blocks {
+		out = append(out, fmt.Sprintf(_BLOCK, b.Divide, b.Check, b.Offset))
+	}
+
+	return strings.Join(out, "\n")
+}
+
+var testBlocks = []Block{
+	{1, 12, 4},
+	{1, 11, 11},
+	{1, 13, 5},
+	{1, 11, 11},
+	{1, 14, 14},
+	{26, -10, 7},
+	{1, 11, 11},
+	{26, -9, 4},
+	{26, -3, 6},
+	{1, 13, 5},
+	{26, -5, 9},
+	{26, -10, 12},
+	{26, -4, 14},
+	{26, -5, 14},
+}
+
+func Test_ModelNumbers(t *testing.T) {
+	program, err := ParseProgram(strings.NewReader(monad(testBlocks)))
+	require.NoError(t, err)
+
+	blocks, err := Blocks(program)
+	require.NoError(t, err)
+	assert.Equal(t, testBlocks, blocks)
+
+	constraints, err := Constraints(blocks)
+	require.NoError(t, err)
+	assert.Equal(t, []Constraint{
+		{I: 4, J: 5, Diff: 4},
+		{I: 6, J: 7, Diff: 2},
+		{I: 3, J: 8, Diff: 8},
+		{I: 9, J: 10, Diff: 0},
+		{I: 2, J: 11, Diff: -5},
+		{I: 1, J: 12, Diff: 7},
+		{I: 0, J: 13, Diff: -1},
+	}, constraints)
+
+	largest, smallest, err := ModelNumbers(program)
+	require.NoError(t, err)
+	assert.Equal(t, "92915979999498", Format(largest))
+	assert.Equal(t, "21611513911181", Format(smallest))
+
+	// Nudging any digit of the largest model number up either breaks
+	// one of the constraints or goes past 9.
+	for i := range largest {
+		if largest[i] == 9 {
+			continue
+		}
+
+		digits := append([]int(nil), largest...)
+		digits[i]++
+		assert.Error(t, Validate(program, digits), Format(digits))
+	}
+}
+
+func Test_Blocks(t *testing.T) {
+	program, err := ParseProgram(strings.NewReader(monad(testBlocks)))
+	require.NoError(t, err)
+
+	_, err = Blocks(program[1:])
+	assert.Error(t, err)
+
+	tampered := append(Program(nil), program...)
+	tampered[20] = Instruction{Op: Add, A: X, B: Operand{Register: W}}
+	_, err = Blocks(tampered)
+	assert.Error(t, err)
+
+	conditional := append([]Block(nil), testBlocks...)
+	conditional[0].Check = 3
+
+	program, err = ParseProgram(strings.NewReader(monad(conditional)))
+	require.NoError(t, err)
+
+	_, err = Blocks(program)
+	assert.Error(t, err)
+}
+
+func Test_Solution(t *testing.T) {
+	// The binary conversion program from the puzzle's examples is a
+	// perfectly good program to Run but it's no MONAD.
+	var s Solution
+	require.NoError(t, s.Load(strings.NewReader("inp w\nadd z w\nmod z 2\ndiv w 2\nadd y w\nmod y 2\ndiv w 2\nadd x w\nmod x 2\ndiv w 2\nmod w 2")))
+
+	registers, err := s.Operations()[0].Call([]int{6})
+	require.NoError(t, err)
+	assert.Equal(t, Registers{W: 0, X: 1, Y: 1, Z: 0}.String(), registers)
+
+	_, err = s.PartOne()
+	assert.Error(t, err)
+
+	_, err = s.PartTwo()
+	assert.Error(t, err)
+
+	require.NoError(t, s.Load(strings.NewReader(monad(testBlocks))))
+
+	largest, err := s.PartOne()
+	require.NoError(t, err)
+	assert.Equal(t, "92915979999498", largest)
+
+	smallest, err := s.PartTwo()
+	require.NoError(t, err)
+	assert.Equal(t, "21611513911181", smallest)
+}
@@ -0,0 +1,85 @@
+package day24
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  24,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
+type Solution struct {
+	program Program
+}
+
+func (s *Solution) Name() string {
+	return "Arithmetic Logic Unit"
+}
+
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
+// Load only parses the program, so any program at all can be loaded and
+// Run, and it's left to the parts to complain when it turns out not to be
+// MONAD. Each part does the whole analysis over again, but it's over in
+// microseconds and lets them go in parallel.
+func (s *Solution) Load(r io.Reader) error {
+	program, err := ParseProgram(r)
+	s.program = program
+
+	return err
+}
+
+func (s *Solution) PartOne() (string, error) {
+	largest, _, err := ModelNumbers(s.program)
+	if err != nil {
+		return "", err
+	}
+
+	return Format(largest), nil
+}
+
+func (s *Solution) PartTwo() (string, error) {
+	_, smallest, err := ModelNumbers(s.program)
+	if err != nil {
+		return "", err
+	}
+
+	return Format(smallest), nil
+}
+
+func (s *Solution) Operations() []registry.Operation {
+	return []registry.Operation{
+		{
+			Name:  "Run",
+			Args:  []string{"input"},
+			Usage: "run the program with each digit of input as an inp, and show the registers it ends with",
+			Call: func(args []int) (string, error) {
+				if args[0] < 0 {
+					return "", errors.New("input can't be negative")
+				}
+
+				var input []int
+				for _, c := range fmt.Sprint(args[0]) {
+					input = append(input, int(c-'0'))
+				}
+
+				registers, err := Run(s.program, input)
+				if err != nil {
+					return "", err
+				}
+
+				return registers.String(), nil
+			},
+		},
+	}
+}
@@ -0,0 +1,93 @@
+package day25
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/stntngo/advent-2021/go/grid"
+)
+
+const (
+	_EMPTY = '.'
+	_EAST  = '>'
+	_SOUTH = 'v'
+)
+
+// Herd is the sea floor and the two herds of sea cucumbers on it, with each
+// cell holding the byte it's drawn with. Anything that moves off one edge of
+// the sea floor comes back in on the other.
+type Herd struct {
+	*grid.Dense
+}
+
+func (h Herd) Copy() Herd {
+	return Herd{h.Dense.Copy()}
+}
+
+// Step moves the east-facing herd and then the south-facing herd, returning
+// how many sea cucumbers moved between them. Every sea cucumber in a herd
+// looks at the space in front of it before any of them move, so the ones
+// that move in each half of the step have to be found before any of them
+// actually do.
+func (h *Herd) Step() int {
+	return h.move(_EAST, grid.Point{X: 1}) + h.move(_SOUTH, grid.Point{Y: 1})
+}
+
+func (h *Herd) move(kind int, direction grid.Point) int {
+	bounds := h.Bounds()
+
+	var movers []grid.Point
+	h.Each(func(p grid.Point, cell int) {
+		if cell == kind && h.At(bounds.Wrap(p.Add(direction))) == _EMPTY {
+			movers = append(movers, p)
+		}
+	})
+
+	for _, p := range movers {
+		h.Set(p, _EMPTY)
+		h.Set(bounds.Wrap(p.Add(direction)), kind)
+	}
+
+	return len(movers)
+}
+
+// String draws the sea floor the same way the puzzle does.
+func (h Herd) String() string {
+	return h.Render(func(cell int) byte {
+		return byte(cell)
+	})
+}
+
+// Frames draws the herd before it moves at all and then again after each of
+// up to n steps, stopping early at the first step where nothing moves. It
+// works on a Copy so the herd itself never moves.
+func Frames(h Herd, n int) []string {
+	h = h.Copy()
+
+	frames := []string{h.String()}
+	for i := 0; i < n; i++ {
+		if h.Step() == 0 {
+			break
+		}
+
+		frames = append(frames, h.String())
+	}
+
+	return frames
+}
+
+func ParseHerd(r io.Reader) (Herd, error) {
+	d, err := grid.Parse(r, func(c byte) (int, error) {
+		switch c {
+		case _EMPTY, _EAST, _SOUTH:
+			return int(c), nil
+		}
+
+		return 0, fmt.Errorf("unexpected %q on the sea floor", c)
+	})
+	if err != nil {
+		return Herd{}, err
+	}
+
+	return Herd{d}, nil
+}
@@ -0,0 +1,92 @@
+package day25
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stntngo/advent-2021/go/sim"
+)
+
+var testCase = `v...>>.vv>
+.vv>>.vv..
+>>.>v>...v
+>>v>>.>.v.
+v>v.vv.v..
+>.>>..v...
+.vv..>.>v.
+v.v..>>v.v
+....v..v.>`
+
+func Test_Step(t *testing.T) {
+	herd, err := ParseHerd(strings.NewReader("...>>>>>..."))
+	require.NoError(t, err)
+
+	frames := Frames(herd, 2)
+	assert.Equal(t, []string{"...>>>>>...\n", "...>>>>.>..\n", "...>>>.>.>.\n"}, frames)
+	assert.Equal(t, "...>>>>>...\n", herd.String())
+
+	herd, err = ParseHerd(strings.NewReader(`..........
+.>v....v..
+.......>..
+..........`))
+	require.NoError(t, err)
+
+	herd.Step()
+	assert.Equal(t, `..........
+.>........
+..v....v>.
+..........
+`, herd.String())
+}
+
+func Test_Wrap(t *testing.T) {
+	herd, err := ParseHerd(strings.NewReader(`...>...
+.......
+......>
+v.....>
+......>
+.......
+..vvv..`))
+	require.NoError(t, err)
+
+	frames := Frames(herd, 4)
+	require.Len(t, frames, 5)
+	assert.Equal(t, `>......
+..v....
+..>.v..
+.>.v...
+...>...
+.......
+v......
+`, frames[4])
+}
+
+func Test_Settle(t *testing.T) {
+	herd, err := ParseHerd(strings.NewReader(testCase))
+	require.NoError(t, err)
+
+	steps, ok := sim.Until(&herd, 1000, sim.Settled)
+	require.True(t, ok)
+	assert.Equal(t, 58, steps)
+	assert.Equal(t, `..>>v>vv..
+..v.>>vv..
+..>>v>>vv.
+..>>>>>vv.
+v......>vv
+v>v....>>v
+vvv.....>>
+>vv......>
+.>v.vv.v..
+`, herd.String())
+}
+
+func Test_ParseHerd(t *testing.T) {
+	_, err := ParseHerd(strings.NewReader("..>\n.v"))
+	assert.Error(t, err)
+
+	_, err = ParseHerd(strings.NewReader("..<"))
+	assert.Error(t, err)
+}
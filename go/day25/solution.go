@@ -0,0 +1,86 @@
+package day25
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/stntngo/advent-2021/go/registry"
+	"github.com/stntngo/advent-2021/go/sim"
+)
+
+func init() {
+	registry.Register(registry.Puzzle{
+		Year: 2021,
+		Day:  25,
+		New:  func() registry.Solution { return new(Solution) },
+	})
+}
+
+// _LIMIT is how many steps to wait for the sea cucumbers to stop moving. A
+// herd can go round and round the sea floor forever, and those that do give
+// up long before anything gets here.
+const _LIMIT = 100000
+
+type Solution struct {
+	herd Herd
+}
+
+func (s *Solution) Name() string {
+	return "Sea Cucumber"
+}
+
+func (s *Solution) ConcurrentParts() bool {
+	return true
+}
+
+func (s *Solution) Load(r io.Reader) error {
+	herd, err := ParseHerd(r)
+	if err != nil {
+		return err
+	}
+
+	s.herd = herd
+
+	return nil
+}
+
+func (s *Solution) PartOne() (string, error) {
+	herd := s.herd.Copy()
+
+	steps, ok := sim.Until(&herd, _LIMIT, sim.Settled)
+	if !ok {
+		return "", fmt.Errorf("the sea cucumbers are still moving after %v steps", steps)
+	}
+
+	return strconv.Itoa(steps), nil
+}
+
+// PartTwo has no puzzle of its own, it's the last star of the calendar.
+func (s *Solution) PartTwo() (string, error) {
+	return "Merry Christmas!", nil
+}
+
+func (s *Solution) Operations() []registry.Operation {
+	return []registry.Operation{
+		{
+			Name:  "Frames",
+			Args:  []string{"steps"},
+			Usage: "draw the sea floor after each of the given number of steps",
+			Call: func(args []int) (string, error) {
+				if args[0] < 0 {
+					return "", errors.New("steps can't be negative")
+				}
+
+				var b strings.Builder
+				for step, frame := range Frames(s.herd, args[0]) {
+					fmt.Fprintf(&b, "\nAfter %v steps:\n%v", step, frame)
+				}
+
+				return b.String(), nil
+			},
+		},
+	}
+}
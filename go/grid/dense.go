@@ -0,0 +1,177 @@
+package grid
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Dense is a grid with a value for every cell from 0,0 to its width and
+// height, stored row by row in a single slice.
+type Dense struct {
+	width, height int
+	cells         []int
+}
+
+func NewDense(width, height int) *Dense {
+	return &Dense{
+		width:  width,
+		height: height,
+		cells:  make([]int, width*height),
+	}
+}
+
+func (d *Dense) Width() int {
+	return d.width
+}
+
+func (d *Dense) Height() int {
+	return d.height
+}
+
+// Len is the number of cells in the grid.
+func (d *Dense) Len() int {
+	return len(d.cells)
+}
+
+func (d *Dense) Bounds() Bounds {
+	return Bounds{Max: Point{d.width - 1, d.height - 1}}
+}
+
+func (d *Dense) Contains(p Point) bool {
+	return p.X >= 0 && p.X < d.width && p.Y >= 0 && p.Y < d.height
+}
+
+// At returns the value in the cell at p, which has to be on the grid.
+func (d *Dense) At(p Point) int {
+	return d.cells[d.index(p)]
+}
+
+func (d *Dense) Set(p Point, value int) {
+	d.cells[d.index(p)] = value
+}
+
+func (d *Dense) index(p Point) int {
+	if !d.Contains(p) {
+		panic(fmt.Sprintf("grid: %v is outside of a %vx%v grid", p, d.width, d.height))
+	}
+
+	return p.Y*d.width + p.X
+}
+
+// Neighbors returns the neighbors of p that are on the grid.
+func (d *Dense) Neighbors(p Point, n Neighborhood) []Point {
+	out := make([]Point, 0, len(n))
+	for _, offset := range n {
+		neighbor := p.Add(offset)
+		if d.Contains(neighbor) {
+			out = append(out, neighbor)
+		}
+	}
+
+	return out
+}
+
+// WrappedNeighbors returns the neighbors of p, with any that fall off one
+// edge of the grid wrapped around to the opposite edge.
+func (d *Dense) WrappedNeighbors(p Point, n Neighborhood) []Point {
+	out := n.Around(p)
+	for i, neighbor := range out {
+		out[i] = d.Bounds().Wrap(neighbor)
+	}
+
+	return out
+}
+
+// Each calls f with every cell of the grid and its value, row by row.
+func (d *Dense) Each(f func(p Point, value int)) {
+	for i, value := range d.cells {
+		f(Point{i % d.width, i / d.width}, value)
+	}
+}
+
+func (d *Dense) Copy() *Dense {
+	return &Dense{
+		width:  d.width,
+		height: d.height,
+		cells:  append([]int(nil), d.cells...),
+	}
+}
+
+// Render draws the grid a row to a line, drawing each cell with whatever
+// cell returns for its value.
+func (d *Dense) Render(cell func(value int) byte) string {
+	var b strings.Builder
+	for i, value := range d.cells {
+		b.WriteByte(cell(value))
+		if (i+1)%d.width == 0 {
+			b.WriteByte('\n')
+		}
+	}
+
+	return b.String()
+}
+
+// Digit draws a cell holding a single digit as that digit, for rendering a
+// grid that came from ParseDigits back the way it was read.
+func Digit(value int) byte {
+	return byte('0' + value)
+}
+
+// Parse reads a grid a row to a line, turning each byte into a value with
+// cell. Blank lines are skipped but every other line has to be the same
+// length.
+func Parse(r io.Reader, cell func(c byte) (int, error)) (*Dense, error) {
+	scanner := bufio.NewScanner(r)
+
+	d := new(Dense)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if d.width == 0 {
+			d.width = len(line)
+		}
+
+		if len(line) != d.width {
+			return nil, fmt.Errorf("row %v is %v wide, expected %v", d.height+1, len(line), d.width)
+		}
+
+		for i := 0; i < len(line); i++ {
+			value, err := cell(line[i])
+			if err != nil {
+				return nil, fmt.Errorf("row %v: %w", d.height+1, err)
+			}
+
+			d.cells = append(d.cells, value)
+		}
+
+		d.height++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if d.height == 0 {
+		return nil, errors.New("empty grid")
+	}
+
+	return d, nil
+}
+
+// ParseDigits reads a grid of single digits, like the heights on day 9 or
+// the octopuses on day 11.
+func ParseDigits(r io.Reader) (*Dense, error) {
+	return Parse(r, func(c byte) (int, error) {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("expected a digit, got %q", c)
+		}
+
+		return int(c - '0'), nil
+	})
+}
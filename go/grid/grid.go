@@ -0,0 +1,127 @@
+// Package grid is the two dimensional grid that turns up in puzzle after
+// puzzle: heights on day 9, octopuses on day 11, chitons on day 15, vents on
+// day 5 and dots on day 13. Dense is for grids with something in every cell,
+// usually read straight from a block of digits. Sparse is for grids that are
+// mostly empty, or that don't have any natural bounds to begin with.
+//
+// Both of them only ever hold ints. That covers every puzzle so far, and
+// where a cell really holds something else, like a sea cucumber, the byte it
+// was drawn with does just as well.
+package grid
+
+import "fmt"
+
+// Point is a cell of a grid, with X growing to the right and Y growing down
+// the same way the puzzle inputs are laid out.
+type Point struct {
+	X, Y int
+}
+
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("%v,%v", p.X, p.Y)
+}
+
+// Neighborhood is the set of offsets that count as neighbors of a cell.
+type Neighborhood []Point
+
+var (
+	// Four is the cells directly above, left, right and below.
+	Four = Neighborhood{{0, -1}, {-1, 0}, {1, 0}, {0, 1}}
+
+	// Eight adds the diagonals to Four.
+	Eight = Neighborhood{
+		{-1, -1}, {0, -1}, {1, -1},
+		{-1, 0}, {1, 0},
+		{-1, 1}, {0, 1}, {1, 1},
+	}
+)
+
+// Around returns every neighbor of p, without any regard for whether they're
+// on a grid or not.
+func (n Neighborhood) Around(p Point) []Point {
+	out := make([]Point, len(n))
+	for i, offset := range n {
+		out[i] = p.Add(offset)
+	}
+
+	return out
+}
+
+// Bounds is the rectangle of cells from Min to Max, inclusive of both.
+type Bounds struct {
+	Min, Max Point
+}
+
+func (b Bounds) Width() int {
+	return b.Max.X - b.Min.X + 1
+}
+
+func (b Bounds) Height() int {
+	return b.Max.Y - b.Min.Y + 1
+}
+
+func (b Bounds) Contains(p Point) bool {
+	return p.X >= b.Min.X && p.X <= b.Max.X && p.Y >= b.Min.Y && p.Y <= b.Max.Y
+}
+
+// Extend grows the bounds just enough to contain p.
+func (b Bounds) Extend(p Point) Bounds {
+	if p.X < b.Min.X {
+		b.Min.X = p.X
+	}
+
+	if p.Y < b.Min.Y {
+		b.Min.Y = p.Y
+	}
+
+	if p.X > b.Max.X {
+		b.Max.X = p.X
+	}
+
+	if p.Y > b.Max.Y {
+		b.Max.Y = p.Y
+	}
+
+	return b
+}
+
+// Grow widens the bounds by n cells on every side.
+func (b Bounds) Grow(n int) Bounds {
+	return Bounds{
+		Min: Point{b.Min.X - n, b.Min.Y - n},
+		Max: Point{b.Max.X + n, b.Max.Y + n},
+	}
+}
+
+// Wrap brings p back inside the bounds as though they wrapped around from
+// one edge to the opposite one, like the sea floor on day 25.
+func (b Bounds) Wrap(p Point) Point {
+	return Point{
+		X: b.Min.X + mod(p.X-b.Min.X, b.Width()),
+		Y: b.Min.Y + mod(p.Y-b.Min.Y, b.Height()),
+	}
+}
+
+func mod(a, n int) int {
+	return (a%n + n) % n
+}
+
+// Points lists every cell in the bounds, row by row.
+func (b Bounds) Points() []Point {
+	if b.Width() <= 0 || b.Height() <= 0 {
+		return nil
+	}
+
+	out := make([]Point, 0, b.Width()*b.Height())
+	for y := b.Min.Y; y <= b.Max.Y; y++ {
+		for x := b.Min.X; x <= b.Max.X; x++ {
+			out = append(out, Point{x, y})
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,115 @@
+package grid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCase = `2199943210
+3987894921
+9856789892
+8767896789
+9899965678`
+
+func Test_ParseDigits(t *testing.T) {
+	d, err := ParseDigits(strings.NewReader(testCase))
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, d.Width())
+	assert.Equal(t, 5, d.Height())
+	assert.Equal(t, 50, d.Len())
+	assert.Equal(t, Bounds{Max: Point{9, 4}}, d.Bounds())
+	assert.Equal(t, 2, d.At(Point{0, 0}))
+	assert.Equal(t, 8, d.At(Point{9, 4}))
+	assert.Equal(t, testCase+"\n", d.Render(Digit))
+
+	for _, input := range []string{"", "123\n12", "12a"} {
+		_, err := ParseDigits(strings.NewReader(input))
+		assert.Error(t, err, input)
+	}
+}
+
+func Test_Dense(t *testing.T) {
+	d := NewDense(3, 2)
+	d.Set(Point{2, 1}, 7)
+
+	c := d.Copy()
+	c.Set(Point{0, 0}, 1)
+
+	assert.Equal(t, "000\n007\n", d.Render(Digit))
+	assert.Equal(t, "100\n007\n", c.Render(Digit))
+
+	assert.True(t, d.Contains(Point{2, 1}))
+	assert.False(t, d.Contains(Point{3, 1}))
+	assert.False(t, d.Contains(Point{0, -1}))
+	assert.Panics(t, func() { d.At(Point{3, 0}) })
+
+	var sum int
+	var order []Point
+	d.Each(func(p Point, value int) {
+		sum += value
+		order = append(order, p)
+	})
+
+	assert.Equal(t, 7, sum)
+	assert.Equal(t, d.Bounds().Points(), order)
+}
+
+func Test_Neighbors(t *testing.T) {
+	d := NewDense(3, 3)
+
+	assert.ElementsMatch(t, []Point{{1, 0}, {0, 1}}, d.Neighbors(Point{0, 0}, Four))
+	assert.ElementsMatch(t, []Point{{1, 0}, {0, 1}, {1, 1}}, d.Neighbors(Point{0, 0}, Eight))
+	assert.Len(t, d.Neighbors(Point{1, 1}, Eight), 8)
+
+	assert.ElementsMatch(
+		t,
+		[]Point{{0, 2}, {2, 0}, {1, 0}, {0, 1}},
+		d.WrappedNeighbors(Point{0, 0}, Four),
+	)
+
+	s := Sparse{{5, 5}: 1, {6, 6}: 1, {5, 7}: 1}
+	assert.Equal(t, []Point{{6, 6}}, s.Neighbors(Point{5, 5}, Eight))
+	assert.Empty(t, s.Neighbors(Point{5, 5}, Four))
+}
+
+func Test_Bounds(t *testing.T) {
+	b := Bounds{Min: Point{-1, -1}, Max: Point{1, 2}}
+	assert.Equal(t, 3, b.Width())
+	assert.Equal(t, 4, b.Height())
+	assert.Len(t, b.Points(), 12)
+
+	assert.Equal(t, Point{1, -1}, b.Wrap(Point{-2, 3}))
+	assert.Equal(t, Point{-1, 2}, b.Wrap(Point{2, -2}))
+	assert.Equal(t, Point{0, 0}, b.Wrap(Point{0, 0}))
+
+	assert.Equal(t, Bounds{Min: Point{-1, -3}, Max: Point{5, 2}}, b.Extend(Point{5, -3}))
+	assert.Equal(t, Bounds{Min: Point{-3, -3}, Max: Point{3, 4}}, b.Grow(2))
+	assert.Empty(t, Bounds{Min: Point{1, 1}}.Points())
+}
+
+func Test_Sparse(t *testing.T) {
+	s := make(Sparse)
+
+	_, ok := s.Bounds()
+	assert.False(t, ok)
+
+	s[Point{2, -1}]++
+	s[Point{4, 1}]++
+	s[Point{4, 1}]++
+
+	b, ok := s.Bounds()
+	require.True(t, ok)
+	assert.Equal(t, Bounds{Min: Point{2, -1}, Max: Point{4, 1}}, b)
+
+	assert.Equal(t, "1..\n...\n..2\n", s.Render(b, func(value int, ok bool) byte {
+		if !ok {
+			return '.'
+		}
+
+		return Digit(value)
+	}))
+}
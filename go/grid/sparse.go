@@ -0,0 +1,57 @@
+package grid
+
+import "strings"
+
+// Sparse is a grid that only holds the cells that have been given a value,
+// which makes it a natural fit for counting things up cell by cell.
+type Sparse map[Point]int
+
+// Bounds is the smallest Bounds containing every cell in the grid, which is
+// only meaningful when the grid isn't empty.
+func (s Sparse) Bounds() (Bounds, bool) {
+	var (
+		b     Bounds
+		found bool
+	)
+
+	for p := range s {
+		if !found {
+			b = Bounds{Min: p, Max: p}
+			found = true
+			continue
+		}
+
+		b = b.Extend(p)
+	}
+
+	return b, found
+}
+
+// Neighbors returns the neighbors of p that are in the grid.
+func (s Sparse) Neighbors(p Point, n Neighborhood) []Point {
+	out := make([]Point, 0, len(n))
+	for _, offset := range n {
+		neighbor := p.Add(offset)
+		if _, ok := s[neighbor]; ok {
+			out = append(out, neighbor)
+		}
+	}
+
+	return out
+}
+
+// Render draws every cell within b a row to a line, drawing each one with
+// whatever cell returns for its value and whether it's in the grid at all.
+func (s Sparse) Render(b Bounds, cell func(value int, ok bool) byte) string {
+	var sb strings.Builder
+	for y := b.Min.Y; y <= b.Max.Y; y++ {
+		for x := b.Min.X; x <= b.Max.X; x++ {
+			value, ok := s[Point{x, y}]
+			sb.WriteByte(cell(value, ok))
+		}
+
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// latest is the baseline revision that stands for whatever was most
+// recently recorded for each day, regardless of which revision it was.
+const latest = "latest"
+
+// HistoryEntry is one day's benchmark as it was saved to the history file.
+type HistoryEntry struct {
+	Name     string      `json:"name"`
+	Recorded time.Time   `json:"recorded"`
+	Bench    benchRecord `json:"bench"`
+}
+
+// History is the on disk record of past benchmarks, keyed first by the git
+// revision they were run against and then by day. Benchmarking the same
+// revision again replaces what was saved for it before.
+type History map[string]map[int]HistoryEntry
+
+// LoadHistory reads the history file at path. A file that doesn't exist
+// yet is just an empty history.
+func LoadHistory(path string) (History, error) {
+	b, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(History), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	h := make(History)
+	if err := json.Unmarshal(b, &h); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// Save writes the history out to path. It goes through a temporary file
+// so that an interrupted run can't leave a half written history behind.
+func (h History) Save(path string) error {
+	b, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Record adds every successfully benchmarked day in results to the
+// history under the given revision.
+func (h History) Record(revision string, results []Result, now time.Time) {
+	days, ok := h[revision]
+	if !ok {
+		days = make(map[int]HistoryEntry)
+		h[revision] = days
+	}
+
+	for _, result := range results {
+		if result.Bench == nil {
+			continue
+		}
+
+		days[result.Day] = HistoryEntry{
+			Name:     result.Name,
+			Recorded: now,
+			Bench:    *newBenchRecord(result.Bench),
+		}
+	}
+}
+
+// Baseline finds what was recorded for day under revision, returning the
+// revision the entry actually came from so that "latest" can be resolved.
+func (h History) Baseline(revision string, day int) (HistoryEntry, string, bool) {
+	if revision != latest {
+		entry, ok := h[revision][day]
+		return entry, revision, ok
+	}
+
+	var (
+		found HistoryEntry
+		from  string
+		ok    bool
+	)
+
+	for rev, days := range h {
+		entry, exists := days[day]
+		if !exists {
+			continue
+		}
+
+		if !ok || entry.Recorded.After(found.Recorded) {
+			found, from, ok = entry, rev, true
+		}
+	}
+
+	return found, from, ok
+}
+
+// Comparison measures a fresh benchmark against the baseline it was
+// compared to. Ratios above one mean the day got slower or allocates more.
+type Comparison struct {
+	Revision    string
+	MedianRatio float64
+	AllocsRatio float64
+	Regressed   bool
+}
+
+// Compare checks the benchmark in result against the baseline entry. Either
+// ratio rising more than threshold above one counts as a regression, so a
+// threshold of 0.1 tolerates a day being up to 10% slower.
+func Compare(result Result, revision string, base HistoryEntry, threshold float64) *Comparison {
+	if result.Bench == nil {
+		return nil
+	}
+
+	c := &Comparison{
+		Revision:    revision,
+		MedianRatio: ratio(uint64(result.Bench.Total.Median), uint64(base.Bench.Total.MedianNS)),
+		AllocsRatio: ratio(result.Bench.AllocsPerRun, base.Bench.AllocsPerRun),
+	}
+
+	c.Regressed = c.MedianRatio > 1+threshold || c.AllocsRatio > 1+threshold
+
+	return c
+}
+
+// ratio is current/baseline except for a baseline of zero, which can only
+// come up for allocations. Going from nothing to something is treated as
+// though the baseline had been one so it still registers as a regression.
+func ratio(current, baseline uint64) float64 {
+	if baseline == 0 {
+		if current == 0 {
+			return 1
+		}
+
+		baseline = 1
+	}
+
+	return float64(current) / float64(baseline)
+}
+
+// Revision describes the git revision of the working tree, marking it as
+// dirty when there are uncommitted changes since those benchmarks can't be
+// reproduced by checking the revision out again.
+func Revision() string {
+	out, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return "unknown"
+	}
+
+	rev := strings.TrimSpace(string(out))
+
+	status, err := exec.Command("git", "status", "--porcelain").Output()
+	if err == nil && len(strings.TrimSpace(string(status))) > 0 {
+		rev += "-dirty"
+	}
+
+	return rev
+}
@@ -2,106 +2,225 @@ package main
 
 import (
 	"embed"
+	"errors"
+	"flag"
 	"fmt"
-	"io"
 	"os"
 	"time"
 
-	"github.com/olekukonko/tablewriter"
-	"github.com/stntngo/advent-2021/go/day01"
-	"github.com/stntngo/advent-2021/go/day02"
-	"github.com/stntngo/advent-2021/go/day03"
-	"github.com/stntngo/advent-2021/go/day04"
-	"github.com/stntngo/advent-2021/go/day05"
-	"github.com/stntngo/advent-2021/go/day06"
-	"github.com/stntngo/advent-2021/go/day07"
-	"github.com/stntngo/advent-2021/go/day08"
-	"github.com/stntngo/advent-2021/go/day09"
-	"github.com/stntngo/advent-2021/go/day10"
-	"github.com/stntngo/advent-2021/go/day11"
-	"github.com/stntngo/advent-2021/go/day12"
-	"github.com/stntngo/advent-2021/go/day13"
-	"github.com/stntngo/advent-2021/go/day14"
-	"github.com/stntngo/advent-2021/go/day15"
-	"github.com/stntngo/advent-2021/go/day16"
+	"github.com/stntngo/advent-2021/go/registry"
+
+	// Each day registers its Solution with the registry when it's
+	// imported, so adding a day only means adding it here.
+	_ "github.com/stntngo/advent-2021/go/day01"
+	_ "github.com/stntngo/advent-2021/go/day02"
+	_ "github.com/stntngo/advent-2021/go/day03"
+	_ "github.com/stntngo/advent-2021/go/day04"
+	_ "github.com/stntngo/advent-2021/go/day05"
+	_ "github.com/stntngo/advent-2021/go/day06"
+	_ "github.com/stntngo/advent-2021/go/day07"
+	_ "github.com/stntngo/advent-2021/go/day08"
+	_ "github.com/stntngo/advent-2021/go/day09"
+	_ "github.com/stntngo/advent-2021/go/day10"
+	_ "github.com/stntngo/advent-2021/go/day11"
+	_ "github.com/stntngo/advent-2021/go/day12"
+	_ "github.com/stntngo/advent-2021/go/day13"
+	_ "github.com/stntngo/advent-2021/go/day14"
+	_ "github.com/stntngo/advent-2021/go/day15"
+	_ "github.com/stntngo/advent-2021/go/day16"
+	_ "github.com/stntngo/advent-2021/go/day17"
+	_ "github.com/stntngo/advent-2021/go/day18"
+	_ "github.com/stntngo/advent-2021/go/day19"
+	_ "github.com/stntngo/advent-2021/go/day20"
+	_ "github.com/stntngo/advent-2021/go/day21"
+	_ "github.com/stntngo/advent-2021/go/day22"
+	_ "github.com/stntngo/advent-2021/go/day23"
+	_ "github.com/stntngo/advent-2021/go/day24"
+	_ "github.com/stntngo/advent-2021/go/day25"
 )
 
-type Solution interface {
-	Name() string
-	Load(io.Reader) error
-	PartOne() (string, error)
-	PartTwo() (string, error)
-}
+type Solution = registry.Solution
+
+// year is the Advent of Code calendar this runner solves. The registry
+// itself is happy to hold puzzles from any year.
+const year = 2021
+
+const (
+	// exitUsage matches the status the flag package exits with
+	// when it's handed arguments it doesn't understand.
+	exitUsage = 2
+
+	// exitFailed is reserved for runs in which at least one day
+	// errored or panicked, so that scripts can tell a regression
+	// apart from the runner being invoked incorrectly.
+	exitFailed = 3
+
+	// exitMismatch means every day ran to completion but at least
+	// one of them came up with the wrong answer.
+	exitMismatch = 4
+)
 
 //go:embed input
 var inputs embed.FS
 
-var solutions = []Solution{
-	new(day01.Solution),
-	new(day02.Solution),
-	new(day03.Solution),
-	new(day04.Solution),
-	new(day05.Solution),
-	new(day06.Solution),
-	new(day07.Solution),
-	new(day08.Solution),
-	new(day09.Solution),
-	new(day10.Solution),
-	new(day11.Solution),
-	new(day12.Solution),
-	new(day13.Solution),
-	new(day14.Solution),
-	new(day15.Solution),
-	new(day16.Solution),
+// commands are the subcommands that take over from the runner entirely when
+// they're named as the first argument. Each one parses the rest of the
+// arguments itself and returns the status to exit with.
+var commands = map[string]func(args []string) int{
+	"serve": Serve,
+	"repl":  Repl,
 }
 
 func main() {
-	table := make([][]string, 0, len(solutions))
-	ttstart := time.Now()
-	for i, sol := range solutions {
-		func() {
-			tstart := time.Now()
-			f, err := inputs.Open(fmt.Sprintf("input/day-%02d", i+1))
-			if err != nil {
-				panic(err)
-			}
-			defer f.Close()
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			os.Exit(command(os.Args[2:]))
+		}
+	}
 
-			if err := sol.Load(f); err != nil {
-				panic(err)
-			}
+	opts, err := ParseOptions(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
 
-			pone, err := sol.PartOne()
-			if err != nil {
-				panic(err)
-			}
+	if err != nil {
+		os.Exit(exitUsage)
+	}
+
+	if opts.List {
+		for _, puzzle := range registry.Puzzles(year) {
+			fmt.Printf("%2d  %-26s %s\n", puzzle.Day, puzzle.Name(), puzzle.URL())
 
-			ptwo, err := sol.PartTwo()
-			if err != nil {
-				panic(err)
+			if p, ok := puzzle.New().(registry.Parameterized); ok {
+				for _, param := range p.Params() {
+					fmt.Printf("      %-24s %s (default %v)\n", fmt.Sprintf("day%02d.%s", puzzle.Day, param.Name), param.Usage, param.Default)
+				}
 			}
-			tend := time.Now()
-
-			row := []string{
-				fmt.Sprintf("Day %v", i+1),
-				sol.Name(),
-				pone,
-				ptwo,
-				fmt.Sprintf("%s", tend.Sub(tstart)),
+		}
+
+		return
+	}
+
+	days := opts.Days.Days()
+	if opts.ProfileDay != 0 {
+		days = append(days, opts.ProfileDay)
+	}
+
+	for _, day := range days {
+		if _, ok := registry.Lookup(year, day); !ok {
+			fmt.Fprintf(os.Stderr, "no solution for day %v\n", day)
+			os.Exit(exitUsage)
+		}
+	}
+
+	// Catch a mistyped or out of range -param before solving anything
+	// rather than reporting it as a failure of the day it was meant for.
+	for day := range opts.Params {
+		puzzle, ok := registry.Lookup(year, day)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "no solution for day %v\n", day)
+			os.Exit(exitUsage)
+		}
+
+		if _, err := NewSolution(puzzle, opts); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitUsage)
+		}
+	}
+
+	answers, err := opts.LoadAnswers()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "loading answers: %v\n", err)
+		os.Exit(exitUsage)
+	}
+
+	var puzzles []registry.Puzzle
+	for _, puzzle := range registry.Puzzles(year) {
+		if opts.Days.Contains(puzzle.Day) {
+			puzzles = append(puzzles, puzzle)
+		}
+	}
+
+	if opts.Watch {
+		Watch(puzzles[0], opts, answers, os.Stdout, nil)
+		return
+	}
+
+	report := Report{
+		Breakdown:  opts.Breakdown,
+		Concurrent: opts.Concurrent(),
+	}
+
+	profiler, err := NewProfiler(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "profiling: %v\n", err)
+		os.Exit(1)
+	}
+
+	start := time.Now()
+	report.Results, err = RunProfiled(puzzles, opts, profiler)
+	report.Duration = time.Since(start)
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "profiling: %v\n", err)
+		os.Exit(1)
+	}
+
+	for i := range report.Results {
+		answers.Check(&report.Results[i])
+	}
+
+	if opts.History != "" {
+		if err := track(&report, opts); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	if err := formatters[opts.Format](os.Stdout, report); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if report.Failed() {
+		os.Exit(exitFailed)
+	}
+
+	if report.Mismatched() {
+		os.Exit(exitMismatch)
+	}
+}
+
+// track compares the benchmarks in report against the baseline, if one was
+// asked for, and then saves them to the history file.
+func track(report *Report, opts Options) error {
+	history, err := LoadHistory(opts.History)
+	if err != nil {
+		return fmt.Errorf("loading history: %w", err)
+	}
+
+	if opts.Baseline != "" {
+		report.Baseline = opts.Baseline
+		for i, result := range report.Results {
+			entry, rev, ok := history.Baseline(opts.Baseline, result.Day)
+			if !ok {
+				continue
 			}
 
-			table = append(table, row)
-		}()
+			report.Results[i].Comparison = Compare(result, rev, entry, opts.Threshold)
+		}
+	}
+
+	revision := opts.Revision
+	if revision == "" {
+		revision = Revision()
 	}
-	ttend := time.Now()
 
-	w := tablewriter.NewWriter(os.Stdout)
-	w.SetHeader([]string{"Day", "Name", "Part One", "Part Two", "Duration"})
-	w.AppendBulk(table)
-	w.Append([]string{"All Days", "", "", "", fmt.Sprintf("%s", ttend.Sub(ttstart))})
+	history.Record(revision, report.Results, time.Now())
+
+	if err := history.Save(opts.History); err != nil {
+		return fmt.Errorf("saving history: %w", err)
+	}
 
-	fmt.Println("Advent of Code 2021!")
-	w.SetHeaderAlignment(tablewriter.ALIGN_RIGHT)
-	w.SetAlignment(tablewriter.ALIGN_RIGHT)
-	w.Render()
+	return nil
 }
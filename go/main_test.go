@@ -0,0 +1,545 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stntngo/advent-2021/go/day06"
+	"github.com/stntngo/advent-2021/go/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DaySet(t *testing.T) {
+	for _, tt := range []struct {
+		flags    []string
+		expected []int
+	}{
+		{
+			[]string{"3"},
+			[]int{3},
+		},
+		{
+			[]string{"1-5"},
+			[]int{1, 2, 3, 4, 5},
+		},
+		{
+			[]string{"1,4,9-11", "16"},
+			[]int{1, 4, 9, 10, 11, 16},
+		},
+		{
+			[]string{"2-3,3-4"},
+			[]int{2, 3, 4},
+		},
+	} {
+		t.Run(strings.Join(tt.flags, " "), func(t *testing.T) {
+			days := make(DaySet)
+			for _, flag := range tt.flags {
+				require.NoError(t, days.Set(flag))
+			}
+
+			assert.Equal(t, tt.expected, days.Days())
+		})
+	}
+
+	for _, bad := range []string{"0", "5-3", "1-2-3", "x", "1,y"} {
+		t.Run(bad, func(t *testing.T) {
+			assert.Error(t, make(DaySet).Set(bad))
+		})
+	}
+}
+
+func Test_ParamSet(t *testing.T) {
+	params := make(ParamSet)
+	require.NoError(t, params.Set("day06.days2=1000"))
+	require.NoError(t, params.Set("day6.days1=18"))
+	require.NoError(t, params.Set("day15.reps=2"))
+	assert.Equal(t, "day06.days1=18,day06.days2=1000,day15.reps=2", params.String())
+
+	for _, bad := range []string{"day06.days2", "06.days2=1", "day06=1", "dayx.days=1", "day06.=1"} {
+		assert.Error(t, params.Set(bad), bad)
+	}
+
+	opts := Options{Params: params}
+	sol, err := NewSolution(registry.Puzzle{Day: 6, New: func() Solution { return new(day06.Solution) }}, opts)
+	require.NoError(t, err)
+	require.NoError(t, sol.Load(strings.NewReader("3,4,3,1,2")))
+
+	one, err := sol.PartOne()
+	require.NoError(t, err)
+	assert.Equal(t, "26", one)
+
+	answers, err := opts.LoadAnswers()
+	require.NoError(t, err)
+	assert.NotContains(t, answers, 6)
+	assert.Contains(t, answers, 1)
+
+	negative := make(ParamSet)
+	require.NoError(t, negative.Set("day06.days2=-1"))
+	_, err = NewSolution(registry.Puzzle{Day: 6, New: func() Solution { return new(day06.Solution) }}, Options{Params: negative})
+	assert.EqualError(t, err, `invalid value "-1" for days2: must be at least 0`)
+}
+
+func Test_ParseOptions(t *testing.T) {
+	opts, err := ParseOptions([]string{"-day", "6", "-part", "2", "-input", "-"}, ioutil.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, []int{6}, opts.Days.Days())
+	assert.False(t, opts.RunsPart(1))
+	assert.True(t, opts.RunsPart(2))
+
+	_, err = ParseOptions([]string{"-input", "-"}, ioutil.Discard)
+	assert.Error(t, err)
+
+	_, err = ParseOptions([]string{"-part", "3"}, ioutil.Discard)
+	assert.Error(t, err)
+}
+
+type brokenSolution struct{}
+
+func (brokenSolution) Name() string             { return "Broken" }
+func (brokenSolution) Load(io.Reader) error     { return nil }
+func (brokenSolution) PartOne() (string, error) { return "", errors.New("no answer") }
+func (brokenSolution) PartTwo() (string, error) { panic("unreachable") }
+
+func Test_RunDayRecovers(t *testing.T) {
+	result := RunDay(registry.Puzzle{
+		Year: year,
+		Day:  1,
+		New:  func() Solution { return brokenSolution{} },
+	}, Options{})
+
+	assert.True(t, result.Failed())
+	assert.EqualError(t, result.PartOne.Err, "no answer")
+	assert.EqualError(t, result.PartTwo.Err, "panic: unreachable")
+}
+
+// stuckSolution never finishes PartTwo until it's released, and has no
+// idea what a context is, so it can only be timed out through the adapter.
+type stuckSolution struct {
+	release chan struct{}
+}
+
+func (stuckSolution) Name() string             { return "Stuck" }
+func (stuckSolution) Load(io.Reader) error     { return nil }
+func (stuckSolution) PartOne() (string, error) { return "1", nil }
+
+func (s stuckSolution) PartTwo() (string, error) {
+	<-s.release
+	return "2", nil
+}
+
+func Test_RunDayTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	result := RunDay(registry.Puzzle{
+		Year: year,
+		Day:  1,
+		New:  func() Solution { return stuckSolution{release} },
+	}, Options{Timeout: 50 * time.Millisecond})
+
+	assert.True(t, result.Failed())
+	assert.Equal(t, "1", result.PartOne.Value)
+	assert.True(t, result.PartTwo.TimedOut())
+	assert.Equal(t, "TIMEOUT", result.PartTwo.String())
+	assert.Equal(t, "timeout", result.Status())
+}
+
+func Test_Formatters(t *testing.T) {
+	report := Report{
+		Results: []Result{
+			{
+				Day:     6,
+				Name:    "Lanternfish",
+				PartOne: Answer{Value: "5934"},
+				PartTwo: Answer{Err: errors.New("too | many fish")},
+				Timings: Timings{
+					Load:    250 * time.Microsecond,
+					PartOne: time.Millisecond,
+				},
+				Duration: 1500 * time.Microsecond,
+			},
+		},
+		Duration: 2 * time.Millisecond,
+	}
+
+	for name, format := range formatters {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, format(&buf, report))
+			assert.Contains(t, buf.String(), "Lanternfish")
+		})
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSONLines(&buf, report))
+
+	var rec map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+	assert.Equal(t, "5934", rec["part_one"])
+	assert.Equal(t, "too | many fish", rec["part_two_error"])
+	assert.Equal(t, float64(250000), rec["load_duration_ns"])
+	assert.Equal(t, "1ms", rec["part_one_duration"])
+	assert.Equal(t, float64(1500000), rec["duration_ns"])
+	assert.Equal(t, "1.5ms", rec["duration"])
+
+	buf.Reset()
+	require.NoError(t, WriteCSV(&buf, report))
+	assert.Equal(t, `day,name,part_one,part_one_error,part_one_expected,part_one_status,part_two,part_two_error,part_two_expected,part_two_status,load_duration_ns,load_duration,part_one_duration_ns,part_one_duration,part_two_duration_ns,part_two_duration,duration_ns,duration
+6,Lanternfish,5934,,,unknown,,too | many fish,,unknown,250000,250µs,1000000,1ms,0,0s,1500000,1.5ms
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteMarkdown(&buf, report))
+	assert.Contains(t, buf.String(), `ERROR: too \| many fish`)
+}
+
+func Test_NewStats(t *testing.T) {
+	var samples []time.Duration
+	for i := 20; i > 0; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+
+	stats := NewStats(samples)
+	assert.Equal(t, time.Millisecond, stats.Min)
+	assert.Equal(t, 10500*time.Microsecond, stats.Median)
+	assert.Equal(t, 10500*time.Microsecond, stats.Mean)
+	assert.Equal(t, 19*time.Millisecond, stats.P95)
+	assert.InDelta(t, float64(5766*time.Microsecond), float64(stats.StdDev), float64(time.Microsecond))
+
+	assert.Equal(t, Stats{}, NewStats(nil))
+}
+
+type countingSolution struct {
+	loads int
+}
+
+func (s *countingSolution) Name() string { return "Counting" }
+
+func (s *countingSolution) Load(io.Reader) error {
+	s.loads++
+	return nil
+}
+
+func (s *countingSolution) PartOne() (string, error) { return strconv.Itoa(s.loads), nil }
+func (s *countingSolution) PartTwo() (string, error) { return strconv.Itoa(s.loads), nil }
+
+func Test_BenchDayUsesFreshSolutions(t *testing.T) {
+	result := BenchDay(registry.Puzzle{
+		Year: year,
+		Day:  1,
+		New:  func() Solution { return new(countingSolution) },
+	}, Options{BenchRuns: 5})
+
+	require.NotNil(t, result.Bench)
+	assert.Equal(t, 5, result.Bench.Runs)
+	assert.Equal(t, "1", result.PartOne.Value)
+}
+
+func Test_History(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	history, err := LoadHistory(path)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	bench := func(median time.Duration, allocs uint64) Result {
+		return Result{
+			Day:  6,
+			Name: "Lanternfish",
+			Bench: &Benchmark{
+				Runs:         1,
+				Total:        Stats{Median: median},
+				AllocsPerRun: allocs,
+			},
+		}
+	}
+
+	history.Record("old", []Result{bench(10*time.Millisecond, 100)}, time.Unix(1, 0))
+	history.Record("new", []Result{bench(20*time.Millisecond, 100)}, time.Unix(2, 0))
+	require.NoError(t, history.Save(path))
+
+	history, err = LoadHistory(path)
+	require.NoError(t, err)
+
+	entry, rev, ok := history.Baseline(latest, 6)
+	require.True(t, ok)
+	assert.Equal(t, "new", rev)
+	assert.Equal(t, int64(20*time.Millisecond), entry.Bench.Total.MedianNS)
+
+	_, _, ok = history.Baseline("old", 7)
+	assert.False(t, ok)
+
+	entry, rev, ok = history.Baseline("old", 6)
+	require.True(t, ok)
+
+	c := Compare(bench(11*time.Millisecond, 100), rev, entry, 0.2)
+	assert.InDelta(t, 1.1, c.MedianRatio, 0.001)
+	assert.False(t, c.Regressed)
+
+	c = Compare(bench(10*time.Millisecond, 150), rev, entry, 0.2)
+	assert.InDelta(t, 1.5, c.AllocsRatio, 0.001)
+	assert.True(t, c.Regressed)
+}
+
+func Test_Answers(t *testing.T) {
+	answers, err := ParseAnswers([]byte(`{"6": {"part_one": "5934", "part_two": "26984457539"}}`))
+	require.NoError(t, err)
+
+	result := Result{
+		Day:     6,
+		PartOne: Answer{Value: "5934"},
+		PartTwo: Answer{Value: "1"},
+	}
+
+	answers.Check(&result)
+	assert.Equal(t, Pass, result.PartOne.Verdict)
+	assert.Equal(t, Fail, result.PartTwo.Verdict)
+	assert.Equal(t, Fail, result.Verdict())
+	assert.Equal(t, "1 (want 26984457539)", result.PartTwo.String())
+
+	result = Result{
+		Day:     6,
+		PartOne: Answer{Value: "5934"},
+	}
+
+	answers.Check(&result)
+	assert.Equal(t, Pass, result.Verdict())
+
+	result = Result{
+		Day:     7,
+		PartOne: Answer{Value: "37"},
+	}
+
+	answers.Check(&result)
+	assert.Equal(t, Unknown, result.Verdict())
+}
+
+func Test_EmbeddedAnswers(t *testing.T) {
+	answers, err := Options{}.LoadAnswers()
+	require.NoError(t, err)
+
+	for _, puzzle := range registry.Puzzles(year) {
+		assert.Contains(t, answers, puzzle.Day)
+	}
+}
+
+type sleepySolution struct {
+	nap time.Duration
+}
+
+func (s sleepySolution) Name() string         { return s.nap.String() }
+func (s sleepySolution) Load(io.Reader) error { return nil }
+func (s sleepySolution) ConcurrentParts() bool {
+	return true
+}
+
+func (s sleepySolution) PartOne() (string, error) {
+	time.Sleep(s.nap)
+	return "1", nil
+}
+
+func (s sleepySolution) PartTwo() (string, error) {
+	time.Sleep(s.nap)
+	return "2", nil
+}
+
+func Test_RunAllKeepsOrder(t *testing.T) {
+	var puzzles []registry.Puzzle
+	for i, nap := range []time.Duration{40, 10, 30, 0, 20} {
+		nap := nap * time.Millisecond
+		puzzles = append(puzzles, registry.Puzzle{
+			Year: year,
+			Day:  i + 1,
+			New:  func() Solution { return sleepySolution{nap} },
+		})
+	}
+
+	opts := Options{Workers: 5, ParallelParts: true}
+
+	start := time.Now()
+	results := RunAll(puzzles, opts)
+	elapsed := time.Since(start)
+
+	require.Len(t, results, 5)
+	for i, result := range results {
+		assert.Equal(t, i+1, result.Day)
+		assert.Equal(t, "1", result.PartOne.Value)
+		assert.Equal(t, "2", result.PartTwo.Value)
+	}
+
+	// Run one after the other the days would need 200ms between them.
+	assert.Less(t, int64(elapsed), int64(150*time.Millisecond))
+
+	report := Report{Results: results, Duration: elapsed}
+	assert.Greater(t, int64(report.Summed()), int64(report.Duration))
+}
+
+func Test_RunProfiled(t *testing.T) {
+	var puzzles []registry.Puzzle
+	for day := 1; day <= 3; day++ {
+		puzzles = append(puzzles, registry.Puzzle{
+			Year: year,
+			Day:  day,
+			New:  func() Solution { return sleepySolution{} },
+		})
+	}
+
+	dir := t.TempDir()
+	opts, err := ParseOptions([]string{
+		"-cpuprofile", filepath.Join(dir, "cpu.prof"),
+		"-allocprofile", filepath.Join(dir, "allocs.prof"),
+		"-profile-day", "2",
+	}, ioutil.Discard)
+	require.NoError(t, err)
+
+	profiler, err := NewProfiler(opts)
+	require.NoError(t, err)
+
+	results, err := RunProfiled(puzzles, opts, profiler)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	for i, result := range results {
+		assert.Equal(t, i+1, result.Day)
+	}
+
+	for _, name := range []string{"cpu.day02.prof", "allocs.day02.prof"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.NotZero(t, info.Size())
+	}
+
+	assert.Equal(t, "trace.day12", dayFile("trace", 12))
+
+	_, err = ParseOptions([]string{"-profile-day", "2"}, ioutil.Discard)
+	assert.Error(t, err)
+
+	_, err = ParseOptions([]string{"-day", "1", "-trace", "t", "-profile-day", "2"}, ioutil.Discard)
+	assert.Error(t, err)
+}
+
+func Test_Server(t *testing.T) {
+	server := httptest.NewServer(NewServer(year, time.Second))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/days")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var days []dayRecord
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&days))
+	require.NotEmpty(t, days)
+	assert.Equal(t, 1, days[0].Day)
+	assert.Equal(t, "Sonar Sweep", days[0].Name)
+
+	input := "199\n200\n208\n210\n200\n207\n240\n269\n260\n263\n"
+	resp, err = http.Post(server.URL+"/days/1", "text/plain", strings.NewReader(input))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var rec record
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&rec))
+	assert.Equal(t, "7", rec.PartOne)
+	assert.Equal(t, "5", rec.PartTwo)
+
+	resp, err = http.Post(server.URL+"/days/1?part=3", "text/plain", strings.NewReader(input))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Post(server.URL+"/days/26", "text/plain", strings.NewReader(input))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	assert.NoError(t, loopback("127.0.0.1:8080"))
+	assert.NoError(t, loopback("[::1]:8080"))
+	assert.Error(t, loopback(":8080"))
+}
+
+func Test_REPL(t *testing.T) {
+	script := strings.Join([]string{
+		"solve",
+		"day 6",
+		"paste",
+		"3,4,3,1,2",
+		".",
+		"solve 1",
+		"SimulatePopulation 18",
+		"call SimulatePopulation x",
+		"SimulatePopulation 490",
+		"day 14",
+		"load",
+		"ExtendPolymer 10",
+		"ExtendPolymer 100",
+		"nonsense",
+		"quit",
+	}, "\n")
+
+	var out bytes.Buffer
+	require.NoError(t, NewREPL(strings.NewReader(script), &out).Run())
+
+	for _, want := range []string{
+		"error: load an input first",
+		"Day 6: Lanternfish",
+		"part 1: 5934",
+		"26 (",
+		`error: days must be an integer, got "x"`,
+		"ERROR: lanternfish population overflows a uint64",
+		"2027",
+		"ERROR: polymer count overflows a uint64",
+		`error: unknown command or operation "nonsense"`,
+	} {
+		assert.Contains(t, out.String(), want)
+	}
+
+	assert.NotContains(t, out.String(), "part 2")
+}
+
+func Test_Watch(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "fish")
+	require.NoError(t, ioutil.WriteFile(input, []byte("3,4,3,1,2"), 0644))
+
+	opts, err := ParseOptions([]string{"-day", "6", "-input", input, "-watch", "-poll", "10ms"}, ioutil.Discard)
+	require.NoError(t, err)
+
+	puzzle, ok := registry.Lookup(year, 6)
+	require.True(t, ok)
+
+	var out bytes.Buffer
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Watch(puzzle, opts, nil, &out, stop)
+	}()
+
+	// Give the first run a moment and make sure the modification time
+	// moves on even on filesystems with coarse timestamps.
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, ioutil.WriteFile(input, []byte("3,4,3,1,2,1"), 0644))
+	require.NoError(t, os.Chtimes(input, time.Now(), time.Now().Add(time.Second)))
+	time.Sleep(50 * time.Millisecond)
+
+	close(stop)
+	<-done
+
+	assert.Contains(t, out.String(), "5934")
+	assert.Contains(t, out.String(), "was 5934")
+
+	_, err = ParseOptions([]string{"-day", "6", "-watch"}, ioutil.Discard)
+	assert.Error(t, err)
+}
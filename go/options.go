@@ -0,0 +1,390 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+// DaySet is the collection of days picked out on the command line with
+// -day. It implements flag.Value so that the flag can be repeated and
+// each occurrence can itself be a comma separated list of single days
+// and inclusive ranges:
+//
+//	-day 3
+//	-day 1-5
+//	-day 1,4,9-12 -day 16
+//
+// An empty DaySet means no selection was made and every day runs.
+type DaySet map[int]bool
+
+func (d DaySet) String() string {
+	days := d.Days()
+
+	strs := make([]string, len(days))
+	for i, day := range days {
+		strs[i] = strconv.Itoa(day)
+	}
+
+	return strings.Join(strs, ",")
+}
+
+func (d DaySet) Set(s string) error {
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		lo, hi, err := parseDayRange(term)
+		if err != nil {
+			return err
+		}
+
+		for day := lo; day <= hi; day++ {
+			d[day] = true
+		}
+	}
+
+	return nil
+}
+
+// Days returns the selected days in ascending order.
+func (d DaySet) Days() []int {
+	days := make([]int, 0, len(d))
+	for day := range d {
+		days = append(days, day)
+	}
+
+	sort.Ints(days)
+
+	return days
+}
+
+// Contains reports whether the day should be run. An empty DaySet
+// contains every day.
+func (d DaySet) Contains(day int) bool {
+	return len(d) == 0 || d[day]
+}
+
+func parseDayRange(term string) (int, int, error) {
+	parts := strings.Split(term, "-")
+	if len(parts) > 2 {
+		return 0, 0, fmt.Errorf("invalid day range %q", term)
+	}
+
+	lo, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid day %q: %w", parts[0], err)
+	}
+
+	hi := lo
+	if len(parts) == 2 {
+		hi, err = strconv.Atoi(parts[1])
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid day %q: %w", parts[1], err)
+		}
+	}
+
+	if lo < 1 || hi < lo {
+		return 0, 0, fmt.Errorf("invalid day range %q", term)
+	}
+
+	return lo, hi, nil
+}
+
+// ParamSet holds the parameter overrides given with -param, keyed first by
+// day and then by the name of the parameter. Each override names its day
+// and parameter together:
+//
+//	-param day06.days2=400
+//	-param day15.reps=10 -param day01.window=5
+type ParamSet map[int]map[string]string
+
+func (p ParamSet) String() string {
+	var strs []string
+	for day, values := range p {
+		for name, value := range values {
+			strs = append(strs, fmt.Sprintf("day%02d.%s=%s", day, name, value))
+		}
+	}
+
+	sort.Strings(strs)
+
+	return strings.Join(strs, ",")
+}
+
+func (p ParamSet) Set(s string) error {
+	kv := strings.SplitN(s, "=", 2)
+	if len(kv) != 2 {
+		return fmt.Errorf("invalid parameter %q, expected dayNN.name=value", s)
+	}
+
+	key := strings.SplitN(kv[0], ".", 2)
+	if len(key) != 2 || !strings.HasPrefix(key[0], "day") || key[1] == "" {
+		return fmt.Errorf("invalid parameter %q, expected dayNN.name=value", s)
+	}
+
+	day, err := strconv.Atoi(strings.TrimPrefix(key[0], "day"))
+	if err != nil || day < 1 {
+		return fmt.Errorf("invalid day in parameter %q", s)
+	}
+
+	if p[day] == nil {
+		p[day] = make(map[string]string)
+	}
+
+	p[day][key[1]] = kv[1]
+
+	return nil
+}
+
+// Options holds everything the runner was asked to do on the command line.
+type Options struct {
+	Days DaySet
+
+	// Part is 1 or 2 to run only that half of each puzzle, or 0 to
+	// run both.
+	Part int
+
+	// Input overrides the embedded puzzle input. It is either a path
+	// to a file or "-" to read from standard input. Since one input
+	// only ever belongs to one puzzle it requires exactly one day to
+	// be selected.
+	Input string
+
+	// Format names the Formatter used to write out the report.
+	Format string
+
+	// Breakdown shows the Load, PartOne and PartTwo timings in the
+	// table instead of only the total for each day.
+	Breakdown bool
+
+	// BenchRuns and BenchTime switch the runner into benchmarking
+	// mode, where each day is solved at least BenchRuns times and
+	// for at least BenchTime before its statistics are reported.
+	BenchRuns int
+	BenchTime time.Duration
+
+	// History is the file benchmark results are saved to, keyed by
+	// Revision, which defaults to the working tree's git revision.
+	History  string
+	Revision string
+
+	// Baseline names the revision in History to compare a benchmark
+	// against, or "latest" for the most recent entry for each day. A
+	// day is flagged as having regressed when its median time or its
+	// allocations grew by more than Threshold.
+	Baseline  string
+	Threshold float64
+
+	// Answers is a file of expected answers to check the results
+	// against in place of the embedded ones.
+	Answers string
+
+	// List prints the registered puzzles instead of solving them.
+	List bool
+
+	// Workers is how many days are solved at the same time.
+	Workers int
+
+	// ParallelParts runs PartOne and PartTwo at the same time for the
+	// solutions that say that's safe.
+	ParallelParts bool
+
+	// Timeout is how long each day gets before it's given up on, or
+	// zero to wait for as long as it takes.
+	Timeout time.Duration
+
+	// CPUProfile, MemProfile, AllocProfile and Trace are the files
+	// to write each kind of profile to. They cover the whole run
+	// unless ProfileDay picks out a single day to profile on its own.
+	CPUProfile   string
+	MemProfile   string
+	AllocProfile string
+	Trace        string
+	ProfileDay   int
+
+	// Params overrides the constants that days expose through
+	// registry.Parameterized.
+	Params ParamSet
+
+	// Watch solves the selected day again every time the Input file
+	// changes, checking for changes every Poll.
+	Watch bool
+	Poll  time.Duration
+}
+
+// Concurrent reports whether anything is going to be solved in parallel.
+func (o Options) Concurrent() bool {
+	return o.Workers > 1 || o.ParallelParts
+}
+
+// Profiling reports whether any profile was asked for.
+func (o Options) Profiling() bool {
+	return o.CPUProfile != "" || o.MemProfile != "" || o.AllocProfile != "" || o.Trace != ""
+}
+
+// Benchmarking reports whether the runner was asked to benchmark
+// rather than solve each day a single time.
+func (o Options) Benchmarking() bool {
+	return o.BenchRuns > 0 || o.BenchTime > 0
+}
+
+// RunsPart reports whether part n of each puzzle should be solved.
+func (o Options) RunsPart(n int) bool {
+	return o.Part == 0 || o.Part == n
+}
+
+func ParseOptions(args []string, output io.Writer) (Options, error) {
+	opts := Options{
+		Days:   make(DaySet),
+		Params: make(ParamSet),
+	}
+
+	fs := flag.NewFlagSet("advent", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.Var(opts.Days, "day", "days to run, e.g. 3, 1-5 or 1,4,9-12 (default all)")
+	fs.IntVar(&opts.Part, "part", 0, "run only part `n` (1 or 2) of each day")
+	fs.StringVar(&opts.Input, "input", "", "read the puzzle input from `file` instead, or - for stdin")
+	fs.StringVar(&opts.Format, "format", "table", "write results as `format`: "+strings.Join(formatNames(), ", "))
+	fs.BoolVar(&opts.Breakdown, "breakdown", false, "show separate load, part one and part two timings in the table")
+	fs.IntVar(&opts.BenchRuns, "bench", 0, "benchmark each day over at least `n` runs")
+	fs.DurationVar(&opts.BenchTime, "benchtime", 0, "benchmark each day for at least `duration`")
+	fs.StringVar(&opts.History, "history", "", "save benchmark results to the JSON history in `file`")
+	fs.StringVar(&opts.Revision, "revision", "", "save benchmark results under `rev` instead of the current git revision")
+	fs.StringVar(&opts.Baseline, "baseline", "", "compare benchmarks against `rev` from -history, or \"latest\"")
+	fs.Float64Var(&opts.Threshold, "threshold", 0.1, "flag days that got slower or allocate more by over this `fraction` of the baseline")
+	fs.StringVar(&opts.Answers, "answers", "", "check results against the expected answers in `file` (default embedded answers.json)")
+	fs.BoolVar(&opts.List, "list", false, "list the registered days and exit")
+	fs.IntVar(&opts.Workers, "workers", 1, "solve up to `n` days at the same time")
+	fs.BoolVar(&opts.ParallelParts, "parallel-parts", false, "solve both parts of a day at the same time when the day allows it")
+	fs.DurationVar(&opts.Timeout, "timeout", 0, "give up on any day that takes longer than `duration` (default no limit)")
+	fs.StringVar(&opts.CPUProfile, "cpuprofile", "", "write a CPU profile to `file`")
+	fs.StringVar(&opts.MemProfile, "memprofile", "", "write a heap profile to `file`")
+	fs.StringVar(&opts.AllocProfile, "allocprofile", "", "write an allocation profile to `file`")
+	fs.StringVar(&opts.Trace, "trace", "", "write an execution trace to `file`")
+	fs.IntVar(&opts.ProfileDay, "profile-day", 0, "profile only day `n`, naming each profile after it (default the whole run)")
+	fs.Var(opts.Params, "param", "override a day's parameter, e.g. day06.days2=400 (see -list)")
+	fs.BoolVar(&opts.Watch, "watch", false, "solve the day again whenever the -input file changes (changes to the code need a restart)")
+	fs.DurationVar(&opts.Poll, "poll", 500*time.Millisecond, "check the -input file for changes every `interval` with -watch")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: advent [flags]\n       advent serve [flags]\n       advent repl\n\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+
+	// Mirror the flag package and report our own validation failures
+	// alongside the usage text, so callers only have to decide on an
+	// exit code.
+	fail := func(err error) (Options, error) {
+		fmt.Fprintln(fs.Output(), err)
+		fs.Usage()
+		return opts, err
+	}
+
+	if fs.NArg() > 0 {
+		return fail(fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " ")))
+	}
+
+	if opts.Part < 0 || opts.Part > 2 {
+		return fail(fmt.Errorf("-part must be 1 or 2, got %v", opts.Part))
+	}
+
+	if opts.Input != "" && len(opts.Days) != 1 {
+		return fail(errors.New("-input requires exactly one day to be selected with -day"))
+	}
+
+	if opts.Workers < 1 {
+		return fail(fmt.Errorf("-workers must be at least 1, got %v", opts.Workers))
+	}
+
+	// Allocations are counted for the whole process, so they'd be
+	// meaningless with more than one day benchmarking at once.
+	if opts.Workers > 1 && opts.Benchmarking() {
+		return fail(errors.New("-bench and -benchtime can't be combined with -workers"))
+	}
+
+	if opts.BenchRuns < 0 || opts.BenchTime < 0 {
+		return fail(errors.New("-bench and -benchtime can't be negative"))
+	}
+
+	// Asking for a minimum amount of time still needs at least one
+	// run to have something to report.
+	if opts.BenchTime > 0 && opts.BenchRuns == 0 {
+		opts.BenchRuns = 1
+	}
+
+	if (opts.History != "" || opts.Baseline != "") && !opts.Benchmarking() {
+		return fail(errors.New("-history and -baseline require -bench or -benchtime"))
+	}
+
+	// A benchmark of a puzzle variant has no business sitting next to
+	// the real ones, or being compared to them.
+	if opts.History != "" && len(opts.Params) > 0 {
+		return fail(errors.New("-history can't be combined with -param"))
+	}
+
+	if opts.Baseline != "" && opts.History == "" {
+		return fail(errors.New("-baseline requires a -history file to compare against"))
+	}
+
+	if opts.Timeout < 0 {
+		return fail(errors.New("-timeout can't be negative"))
+	}
+
+	if opts.ProfileDay != 0 && !opts.Profiling() {
+		return fail(errors.New("-profile-day requires -cpuprofile, -memprofile, -allocprofile or -trace"))
+	}
+
+	if opts.ProfileDay != 0 && !opts.Days.Contains(opts.ProfileDay) {
+		return fail(fmt.Errorf("-profile-day %v isn't one of the days selected with -day", opts.ProfileDay))
+	}
+
+	if opts.Watch && (opts.Input == "" || opts.Input == "-") {
+		return fail(errors.New("-watch requires an -input file to watch"))
+	}
+
+	if opts.Watch && (opts.Benchmarking() || opts.Profiling()) {
+		return fail(errors.New("-watch can't be combined with benchmarking or profiling"))
+	}
+
+	if opts.Poll <= 0 {
+		return fail(errors.New("-poll must be positive"))
+	}
+
+	if opts.Threshold < 0 {
+		return fail(errors.New("-threshold can't be negative"))
+	}
+
+	if _, ok := formatters[opts.Format]; !ok {
+		return fail(fmt.Errorf("unknown -format %q", opts.Format))
+	}
+
+	return opts, nil
+}
+
+// ReadInput returns the input for the given puzzle, honoring any
+// override given with -input.
+func (o Options) ReadInput(p registry.Puzzle) ([]byte, error) {
+	switch o.Input {
+	case "":
+		return inputs.ReadFile(p.Input())
+	case "-":
+		return ioutil.ReadAll(os.Stdin)
+	default:
+		return ioutil.ReadFile(o.Input)
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strings"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+// Profiler writes out whichever of the CPU, heap and allocation profiles and
+// the execution trace were asked for on the command line. Every file is
+// created up front so that a bad path is reported before anything runs
+// rather than after the whole thing has been solved.
+type Profiler struct {
+	cpu, heap, allocs, trace *os.File
+
+	// rate is the runtime.MemProfileRate to sample allocations at
+	// while the profiler is running.
+	rate int
+}
+
+// NewProfiler creates the files for every profile in opts, or returns nil
+// when there's nothing to profile. When the profiles only cover a single day
+// they're named after it, so -cpuprofile cpu.prof -profile-day 12 writes to
+// cpu.day12.prof.
+func NewProfiler(opts Options) (*Profiler, error) {
+	if !opts.Profiling() {
+		return nil, nil
+	}
+
+	p := &Profiler{rate: runtime.MemProfileRate}
+
+	for _, profile := range []struct {
+		path string
+		file **os.File
+	}{
+		{opts.CPUProfile, &p.cpu},
+		{opts.MemProfile, &p.heap},
+		{opts.AllocProfile, &p.allocs},
+		{opts.Trace, &p.trace},
+	} {
+		if profile.path == "" {
+			continue
+		}
+
+		path := profile.path
+		if opts.ProfileDay != 0 {
+			path = dayFile(path, opts.ProfileDay)
+		}
+
+		f, err := os.Create(path)
+		if err != nil {
+			p.close()
+			return nil, err
+		}
+
+		*profile.file = f
+	}
+
+	// The memory profiles accumulate over the life of the process and
+	// can't be reset, so the only way to keep every other day out of a
+	// single day's profile is to stop sampling until that day starts.
+	// Whatever was allocated before we got here, package initialization
+	// included, will still show up in it.
+	if opts.ProfileDay != 0 {
+		runtime.MemProfileRate = 0
+	}
+
+	return p, nil
+}
+
+// dayFile inserts the day into path just ahead of its extension.
+func dayFile(path string, day int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s.day%02d%s", strings.TrimSuffix(path, ext), day, ext)
+}
+
+// Start begins the CPU profile and the trace and starts sampling
+// allocations.
+func (p *Profiler) Start() error {
+	runtime.MemProfileRate = p.rate
+
+	if p.cpu != nil {
+		if err := pprof.StartCPUProfile(p.cpu); err != nil {
+			return err
+		}
+	}
+
+	if p.trace != nil {
+		if err := trace.Start(p.trace); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Stop ends the CPU profile and the trace, writes out the memory profiles
+// and closes every file, returning the first error it came across.
+func (p *Profiler) Stop() error {
+	if p.cpu != nil {
+		pprof.StopCPUProfile()
+	}
+
+	if p.trace != nil {
+		trace.Stop()
+	}
+
+	// Collect first so that the heap profile is up to date rather than
+	// reflecting whenever the last collection happened to be.
+	runtime.GC()
+
+	var errs []error
+	if p.heap != nil {
+		errs = append(errs, pprof.Lookup("heap").WriteTo(p.heap, 0))
+	}
+
+	if p.allocs != nil {
+		errs = append(errs, pprof.Lookup("allocs").WriteTo(p.allocs, 0))
+	}
+
+	runtime.MemProfileRate = 0
+
+	errs = append(errs, p.close())
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Profiler) close() error {
+	var first error
+	for _, f := range []*os.File{p.cpu, p.heap, p.allocs, p.trace} {
+		if f == nil {
+			continue
+		}
+
+		if err := f.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+// RunProfiled is RunAll with the profiler running for either the whole run
+// or, with -profile-day, only the one day. The days on either side of that
+// one are still solved, just not profiled, and since they're run before and
+// after it rather than alongside it nothing else can show up in its profile
+// no matter how many workers there are.
+func RunProfiled(puzzles []registry.Puzzle, opts Options, p *Profiler) ([]Result, error) {
+	if p == nil {
+		return RunAll(puzzles, opts), nil
+	}
+
+	profiled := func(i int) bool {
+		return opts.ProfileDay == 0 || puzzles[i].Day == opts.ProfileDay
+	}
+
+	lo := 0
+	for lo < len(puzzles) && !profiled(lo) {
+		lo++
+	}
+
+	hi := lo
+	for hi < len(puzzles) && profiled(hi) {
+		hi++
+	}
+
+	results := RunAll(puzzles[:lo], opts)
+
+	if err := p.Start(); err != nil {
+		p.Stop()
+		return nil, err
+	}
+
+	results = append(results, RunAll(puzzles[lo:hi], opts)...)
+
+	if err := p.Stop(); err != nil {
+		return nil, err
+	}
+
+	return append(results, RunAll(puzzles[hi:], opts)...), nil
+}
@@ -0,0 +1,95 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// ContextSolution is the cancellable counterpart to Solution. Days whose
+// algorithms can run away with themselves, whether that's a search that
+// never converges or a graph that's far bigger than expected, implement it
+// and check ctx as they go so that the runner can give up on them.
+type ContextSolution interface {
+	Name() string
+	LoadContext(context.Context, io.Reader) error
+	PartOneContext(context.Context) (string, error)
+	PartTwoContext(context.Context) (string, error)
+}
+
+// WithContext returns sol as a ContextSolution. Solutions that already
+// implement it are returned as they are. Everything else is wrapped so that
+// each call runs on its own goroutine and returns ctx.Err() as soon as ctx
+// is done. There's no way to stop the goroutine itself, so it carries on in
+// the background and whatever it eventually returns is thrown away, but the
+// caller at least gets to move on.
+func WithContext(sol Solution) ContextSolution {
+	if cs, ok := sol.(ContextSolution); ok {
+		return cs
+	}
+
+	return adapter{sol}
+}
+
+type adapter struct {
+	sol Solution
+}
+
+func (a adapter) Name() string {
+	return a.sol.Name()
+}
+
+func (a adapter) LoadContext(ctx context.Context, r io.Reader) error {
+	_, err := await(ctx, func() (string, error) {
+		return "", a.sol.Load(r)
+	})
+
+	return err
+}
+
+func (a adapter) PartOneContext(ctx context.Context) (string, error) {
+	return await(ctx, a.sol.PartOne)
+}
+
+func (a adapter) PartTwoContext(ctx context.Context) (string, error) {
+	return await(ctx, a.sol.PartTwo)
+}
+
+type outcome struct {
+	value string
+	err   error
+}
+
+func await(ctx context.Context, f func() (string, error)) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	// Buffered so the goroutine can always deliver its outcome and
+	// exit, even when nobody is waiting for it anymore.
+	done := make(chan outcome, 1)
+
+	go func() {
+		var o outcome
+
+		// A panic on this goroutine can't be recovered by the
+		// caller, so it has to be turned into an error here or it
+		// would take the whole process down with it.
+		defer func() {
+			if r := recover(); r != nil {
+				o.err = fmt.Errorf("panic: %v", r)
+			}
+
+			done <- o
+		}()
+
+		o.value, o.err = f()
+	}()
+
+	select {
+	case o := <-done:
+		return o.value, o.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
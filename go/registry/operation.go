@@ -0,0 +1,24 @@
+package registry
+
+// Operation is one of the building blocks of a day's solution exposed so it
+// can be called with whatever arguments you like, rather than only with the
+// ones baked into PartOne and PartTwo. How many days does it take for the
+// lanternfish to pass a trillion? How does the polymer score grow with each
+// round? Operations answer that sort of question without editing any code.
+type Operation struct {
+	Name string
+
+	// Args names each of the integer arguments Call expects, in the
+	// order it expects them.
+	Args  []string
+	Usage string
+
+	Call func(args []int) (string, error)
+}
+
+// Operator is implemented by solutions with Operations to expose. They're
+// only ever asked for their Operations after a successful Load, so every
+// Operation is free to work with the parsed input.
+type Operator interface {
+	Operations() []Operation
+}
@@ -0,0 +1,162 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Param is one of the puzzle constants a solution lets the runner override,
+// like the 80 and 256 days the lanternfish are simulated for or the size of
+// the sliding window over the sonar readings. Exploring a variant of the
+// puzzle then only takes a flag rather than a fork of the code.
+type Param struct {
+	Name  string
+	Usage string
+
+	// Default is the value the puzzle itself calls for. Its type, which
+	// has to be an int, float64, bool or string, is also the type that
+	// any value given for the Param is parsed as.
+	Default interface{}
+
+	// Check, when it's set, rejects values that parse but make no sense
+	// for the puzzle, like a negative number of days, so that they're
+	// turned away along with any other bad flag before anything is solved.
+	Check func(v interface{}) error
+}
+
+// AtLeast is a Check for an int Param that can't go below min.
+func AtLeast(min int) func(v interface{}) error {
+	return func(v interface{}) error {
+		if n, ok := v.(int); ok && n < min {
+			return fmt.Errorf("must be at least %d", min)
+		}
+
+		return nil
+	}
+}
+
+// Parse converts s into a value of the same type as the Param's Default and
+// makes sure it passes the Param's Check.
+func (p Param) Parse(s string) (interface{}, error) {
+	var (
+		v   interface{}
+		err error
+	)
+
+	switch p.Default.(type) {
+	case int:
+		v, err = strconv.Atoi(s)
+	case float64:
+		v, err = strconv.ParseFloat(s, 64)
+	case bool:
+		v, err = strconv.ParseBool(s)
+	case string:
+		v = s
+	default:
+		return nil, fmt.Errorf("%s has a default of unsupported type %T", p.Name, p.Default)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q for %s: expected %T", s, p.Name, p.Default)
+	}
+
+	if p.Check != nil {
+		if err := p.Check(v); err != nil {
+			return nil, fmt.Errorf("invalid value %q for %s: %w", s, p.Name, err)
+		}
+	}
+
+	return v, nil
+}
+
+// Args holds the values given for a solution's Params. Any Param missing
+// from it, which includes every Param when Args itself is nil, takes its
+// Default, so a solution that was never handed any Args at all just solves
+// the puzzle as written.
+type Args map[string]interface{}
+
+func (a Args) Int(p Param) int {
+	if v, ok := a[p.Name].(int); ok {
+		return v
+	}
+
+	return p.Default.(int)
+}
+
+func (a Args) Float(p Param) float64 {
+	if v, ok := a[p.Name].(float64); ok {
+		return v
+	}
+
+	return p.Default.(float64)
+}
+
+func (a Args) Bool(p Param) bool {
+	if v, ok := a[p.Name].(bool); ok {
+		return v
+	}
+
+	return p.Default.(bool)
+}
+
+func (a Args) String(p Param) string {
+	if v, ok := a[p.Name].(string); ok {
+		return v
+	}
+
+	return p.Default.(string)
+}
+
+// Parameterized is implemented by solutions with Params to expose. SetArgs
+// is called once, before Load, and only when there's something to override.
+type Parameterized interface {
+	Params() []Param
+	SetArgs(Args)
+}
+
+// Configure parses values, keyed by Param name, against the Params of sol
+// and hands the result to sol. It's an error to name a Param that sol
+// doesn't have, including when sol has none at all.
+func Configure(sol Solution, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	p, ok := sol.(Parameterized)
+	if !ok {
+		return fmt.Errorf("%s takes no parameters", sol.Name())
+	}
+
+	params := make(map[string]Param)
+	for _, param := range p.Params() {
+		params[param.Name] = param
+	}
+
+	args := make(Args)
+	for name, value := range values {
+		param, ok := params[name]
+		if !ok {
+			names := make([]string, 0, len(params))
+			for name := range params {
+				names = append(names, name)
+			}
+
+			sort.Strings(names)
+
+			return fmt.Errorf("%s has no parameter %q, only %s", sol.Name(), name, strings.Join(names, ", "))
+		}
+
+		v, err := param.Parse(value)
+		if err != nil {
+			return err
+		}
+
+		args[name] = v
+	}
+
+	p.SetArgs(args)
+
+	return nil
+}
@@ -0,0 +1,127 @@
+// Package registry is where every day's Solution announces itself to the
+// runner. Rather than main keeping a hand maintained list of solutions, and
+// relying on that list's order to work out which input belongs to which day,
+// each day package registers itself from an init function:
+//
+//	func init() {
+//		registry.Register(registry.Puzzle{
+//			Year: 2021,
+//			Day:  1,
+//			New:  func() registry.Solution { return new(Solution) },
+//		})
+//	}
+//
+// and the runner only has to import the package for its side effects.
+package registry
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Solution is the interface every day implements. Load is handed the
+// puzzle input and PartOne and PartTwo return their answers as strings
+// so that numeric and textual answers can be treated the same.
+type Solution interface {
+	Name() string
+	Load(io.Reader) error
+	PartOne() (string, error)
+	PartTwo() (string, error)
+}
+
+// Concurrent is implemented by solutions whose PartOne and PartTwo share no
+// mutable state once Load has returned, which means the runner is free to
+// call them both at the same time.
+type Concurrent interface {
+	ConcurrentParts() bool
+}
+
+// Puzzle describes a registered day.
+type Puzzle struct {
+	Year int
+	Day  int
+
+	// New returns a fresh, unloaded Solution every time it's called
+	// so that callers never have to worry about whatever state an
+	// earlier Load left behind.
+	New func() Solution
+}
+
+// Name is the puzzle's title as reported by its Solution.
+func (p Puzzle) Name() string {
+	return p.New().Name()
+}
+
+// Input is the path of the puzzle's input within the runner's
+// embedded input directory.
+func (p Puzzle) Input() string {
+	return fmt.Sprintf("input/day-%02d", p.Day)
+}
+
+// URL links to the puzzle's description on Advent of Code.
+func (p Puzzle) URL() string {
+	return fmt.Sprintf("https://adventofcode.com/%d/day/%d", p.Year, p.Day)
+}
+
+var (
+	mu      sync.Mutex
+	puzzles = make(map[key]Puzzle)
+)
+
+type key struct {
+	year, day int
+}
+
+// Register makes a puzzle available to the runner. Like database/sql's
+// Register it's meant to be called from init, and it panics if the puzzle
+// is malformed or if the same day is registered twice since either one is
+// a programming mistake rather than something to recover from.
+func Register(p Puzzle) {
+	if p.Day < 1 || p.Day > 25 {
+		panic(fmt.Sprintf("registry: day %d out of range", p.Day))
+	}
+
+	if p.New == nil {
+		panic(fmt.Sprintf("registry: %d day %d has no constructor", p.Year, p.Day))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	k := key{p.Year, p.Day}
+	if _, dup := puzzles[k]; dup {
+		panic(fmt.Sprintf("registry: %d day %d registered twice", p.Year, p.Day))
+	}
+
+	puzzles[k] = p
+}
+
+// Puzzles returns every registered puzzle for the year in day order.
+func Puzzles(year int) []Puzzle {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var out []Puzzle
+	for k, p := range puzzles {
+		if k.year == year {
+			out = append(out, p)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Day < out[j].Day
+	})
+
+	return out
+}
+
+// Lookup finds the puzzle registered for the given year and day.
+func Lookup(year, day int) (Puzzle, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	p, ok := puzzles[key{year, day}]
+	return p, ok
+}
@@ -0,0 +1,115 @@
+package registry
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stub struct{}
+
+func (stub) Name() string             { return "Stub" }
+func (stub) Load(io.Reader) error     { return nil }
+func (stub) PartOne() (string, error) { return "", nil }
+func (stub) PartTwo() (string, error) { return "", nil }
+
+func newStub() Solution {
+	return stub{}
+}
+
+func Test_Register(t *testing.T) {
+	Register(Puzzle{Year: 1999, Day: 3, New: newStub})
+	Register(Puzzle{Year: 1999, Day: 1, New: newStub})
+	Register(Puzzle{Year: 1998, Day: 2, New: newStub})
+
+	days := Puzzles(1999)
+	require.Len(t, days, 2)
+	assert.Equal(t, 1, days[0].Day)
+	assert.Equal(t, 3, days[1].Day)
+
+	p, ok := Lookup(1998, 2)
+	require.True(t, ok)
+	assert.Equal(t, "Stub", p.Name())
+	assert.Equal(t, "input/day-02", p.Input())
+	assert.Equal(t, "https://adventofcode.com/1998/day/2", p.URL())
+
+	_, ok = Lookup(1998, 3)
+	assert.False(t, ok)
+
+	assert.Panics(t, func() {
+		Register(Puzzle{Year: 1999, Day: 1, New: newStub})
+	})
+
+	assert.Panics(t, func() {
+		Register(Puzzle{Year: 1999, Day: 26, New: newStub})
+	})
+
+	assert.Panics(t, func() {
+		Register(Puzzle{Year: 1999, Day: 4})
+	})
+}
+
+type slow struct {
+	stub
+	release chan struct{}
+}
+
+func (s slow) PartOne() (string, error) {
+	<-s.release
+	return "done", nil
+}
+
+func (slow) PartTwo() (string, error) {
+	panic("oops")
+}
+
+func Test_WithContext(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	sol := WithContext(slow{release: release})
+	assert.Equal(t, "Stub", sol.Name())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := sol.PartOneContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = sol.PartTwoContext(context.Background())
+	assert.EqualError(t, err, "panic: oops")
+
+	_, err = sol.PartTwoContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+var _SIZE = Param{Name: "size", Default: 3, Check: AtLeast(1)}
+
+type tunable struct {
+	stub
+	args Args
+}
+
+func (t *tunable) Params() []Param   { return []Param{_SIZE} }
+func (t *tunable) SetArgs(args Args) { t.args = args }
+
+func Test_Configure(t *testing.T) {
+	var sol tunable
+	assert.Equal(t, 3, sol.args.Int(_SIZE))
+
+	require.NoError(t, Configure(&sol, nil))
+	assert.Equal(t, 3, sol.args.Int(_SIZE))
+
+	require.NoError(t, Configure(&sol, map[string]string{"size": "7"}))
+	assert.Equal(t, 7, sol.args.Int(_SIZE))
+
+	assert.EqualError(t, Configure(&sol, map[string]string{"size": "big"}), `invalid value "big" for size: expected int`)
+	assert.EqualError(t, Configure(&sol, map[string]string{"size": "0"}), `invalid value "0" for size: must be at least 1`)
+	assert.Equal(t, 7, sol.args.Int(_SIZE))
+	assert.EqualError(t, Configure(&sol, map[string]string{"width": "1"}), `Stub has no parameter "width", only size`)
+	assert.EqualError(t, Configure(stub{}, map[string]string{"size": "1"}), "Stub takes no parameters")
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+const replHelp = `commands:
+  days                 list the registered days
+  day n                switch to day n
+  load [file]          load the day's embedded input, or the input in file
+  paste                load an input typed or pasted in, ending with a line
+                       holding a single "."
+  solve [part]         solve both parts, or only part 1 or 2
+  ops                  list the operations the day exposes
+  [call] op [args...]  call an operation with integer arguments
+  help                 show this again
+  quit                 leave
+`
+
+// Repl is the repl subcommand, an interactive shell for poking at a day
+// beyond what PartOne and PartTwo do with it.
+func Repl(args []string) int {
+	fs := flag.NewFlagSet("advent repl", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: advent repl\n\n%s", replHelp)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+
+		return exitUsage
+	}
+
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	if err := NewREPL(os.Stdin, os.Stdout).Run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// REPL reads commands one line at a time and runs them against whichever
+// day is currently selected.
+type REPL struct {
+	in  *bufio.Scanner
+	out io.Writer
+
+	puzzle registry.Puzzle
+	sol    Solution
+
+	// loaded is whether sol has successfully loaded an input, which it
+	// needs to have done before it can be solved or operated on.
+	loaded bool
+}
+
+func NewREPL(in io.Reader, out io.Writer) *REPL {
+	return &REPL{
+		in:  bufio.NewScanner(in),
+		out: out,
+	}
+}
+
+// Run reads and runs commands until it's told to quit or runs out of input.
+// Mistakes in a command are reported and then forgotten about, so the only
+// errors Run returns are from reading its input.
+func (r *REPL) Run() error {
+	fmt.Fprintf(r.out, "Advent of Code %v! Type help for a list of commands.\n", year)
+
+	for {
+		fmt.Fprint(r.out, r.prompt())
+		if !r.in.Scan() {
+			fmt.Fprintln(r.out)
+			return r.in.Err()
+		}
+
+		fields := strings.Fields(r.in.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if fields[0] == "quit" || fields[0] == "exit" {
+			return nil
+		}
+
+		if err := r.exec(fields[0], fields[1:]); err != nil {
+			fmt.Fprintf(r.out, "error: %v\n", err)
+		}
+	}
+}
+
+func (r *REPL) prompt() string {
+	if r.sol == nil {
+		return "> "
+	}
+
+	return fmt.Sprintf("day%02d> ", r.puzzle.Day)
+}
+
+func (r *REPL) exec(command string, args []string) error {
+	switch command {
+	case "help":
+		fmt.Fprint(r.out, replHelp)
+		return nil
+	case "days":
+		for _, puzzle := range registry.Puzzles(year) {
+			fmt.Fprintf(r.out, "%2d  %s\n", puzzle.Day, puzzle.Name())
+		}
+
+		return nil
+	case "day":
+		return r.day(args)
+	case "load":
+		return r.load(args)
+	case "paste":
+		return r.paste()
+	case "solve":
+		return r.solve(args)
+	case "ops":
+		return r.ops()
+	case "call":
+		if len(args) == 0 {
+			return errors.New("usage: call op [args...]")
+		}
+
+		return r.call(args[0], args[1:])
+	default:
+		if !r.loaded {
+			return fmt.Errorf("unknown command %q", command)
+		}
+
+		return r.call(command, args)
+	}
+}
+
+func (r *REPL) day(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: day n")
+	}
+
+	day, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid day %q", args[0])
+	}
+
+	puzzle, ok := registry.Lookup(year, day)
+	if !ok {
+		return fmt.Errorf("no solution for day %v", day)
+	}
+
+	r.puzzle = puzzle
+	r.sol = puzzle.New()
+	r.loaded = false
+
+	fmt.Fprintf(r.out, "Day %v: %s\n", puzzle.Day, r.sol.Name())
+
+	return nil
+}
+
+func (r *REPL) load(args []string) error {
+	if r.sol == nil {
+		return errors.New("pick a day first")
+	}
+
+	switch len(args) {
+	case 0:
+		input, err := inputs.ReadFile(r.puzzle.Input())
+		if err != nil {
+			return err
+		}
+
+		return r.loadInput(input)
+	case 1:
+		input, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+
+		return r.loadInput(input)
+	default:
+		return errors.New("usage: load [file]")
+	}
+}
+
+func (r *REPL) paste() error {
+	if r.sol == nil {
+		return errors.New("pick a day first")
+	}
+
+	fmt.Fprintln(r.out, `paste the input, then finish with a line holding a single "."`)
+
+	var input bytes.Buffer
+	for r.in.Scan() {
+		if r.in.Text() == "." {
+			return r.loadInput(input.Bytes())
+		}
+
+		input.WriteString(r.in.Text())
+		input.WriteByte('\n')
+	}
+
+	if err := r.in.Err(); err != nil {
+		return err
+	}
+
+	return r.loadInput(input.Bytes())
+}
+
+// loadInput gives the input to a brand new Solution, since there's no
+// telling what a Solution that's already been loaded will do with another.
+func (r *REPL) loadInput(input []byte) error {
+	sol := r.puzzle.New()
+
+	start := time.Now()
+	if err := safely(func() error {
+		return sol.Load(bytes.NewReader(input))
+	}); err != nil {
+		return fmt.Errorf("load: %w", err)
+	}
+
+	r.sol = sol
+	r.loaded = true
+
+	fmt.Fprintf(r.out, "loaded %v bytes in %v\n", len(input), time.Since(start))
+
+	return nil
+}
+
+func (r *REPL) solve(args []string) error {
+	if !r.loaded {
+		return errors.New("load an input first")
+	}
+
+	var opts Options
+	if len(args) > 1 {
+		return errors.New("usage: solve [part]")
+	}
+
+	if len(args) == 1 {
+		part, err := strconv.Atoi(args[0])
+		if err != nil || part < 1 || part > 2 {
+			return fmt.Errorf("part must be 1 or 2, got %q", args[0])
+		}
+
+		opts.Part = part
+	}
+
+	for n, part := range []func() (string, error){r.sol.PartOne, r.sol.PartTwo} {
+		if !opts.RunsPart(n + 1) {
+			continue
+		}
+
+		answer, elapsed := solve(context.Background(), func(context.Context) (string, error) {
+			return part()
+		})
+
+		fmt.Fprintf(r.out, "part %v: %v (%v)\n", n+1, answer, elapsed)
+	}
+
+	return nil
+}
+
+func (r *REPL) operations() ([]registry.Operation, error) {
+	if !r.loaded {
+		return nil, errors.New("load an input first")
+	}
+
+	operator, ok := r.sol.(registry.Operator)
+	if !ok {
+		return nil, fmt.Errorf("day %v has no operations", r.puzzle.Day)
+	}
+
+	return operator.Operations(), nil
+}
+
+func (r *REPL) ops() error {
+	ops, err := r.operations()
+	if err != nil {
+		return err
+	}
+
+	for _, op := range ops {
+		fmt.Fprintf(r.out, "  %-30s %s\n", signature(op), op.Usage)
+	}
+
+	return nil
+}
+
+func (r *REPL) call(name string, args []string) error {
+	ops, err := r.operations()
+	if err != nil {
+		return err
+	}
+
+	for _, op := range ops {
+		if op.Name != name {
+			continue
+		}
+
+		if len(args) != len(op.Args) {
+			return fmt.Errorf("usage: %s", signature(op))
+		}
+
+		ints := make([]int, len(args))
+		for i, arg := range args {
+			ints[i], err = strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("%s must be an integer, got %q", op.Args[i], arg)
+			}
+		}
+
+		answer, elapsed := solve(context.Background(), func(context.Context) (string, error) {
+			return op.Call(ints)
+		})
+
+		fmt.Fprintf(r.out, "%v (%v)\n", answer, elapsed)
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown command or operation %q", name)
+}
+
+// signature describes how to call op, such as "Paths max".
+func signature(op registry.Operation) string {
+	return strings.Join(append([]string{op.Name}, op.Args...), " ")
+}
@@ -0,0 +1,589 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// Report is everything a single invocation of the runner produced.
+type Report struct {
+	Results  []Result
+	Duration time.Duration
+
+	// Breakdown asks the formats meant for people to show the time
+	// spent in each phase of a day rather than just the total. The
+	// machine readable formats always include every phase.
+	Breakdown bool
+
+	// Baseline is the revision benchmarks were compared against, if
+	// they were compared at all.
+	Baseline string
+
+	// Concurrent is set when days or parts were solved in parallel,
+	// at which point the wall clock Duration no longer tells us how
+	// much work was done and the formats meant for people also show
+	// the summed time.
+	Concurrent bool
+}
+
+// Summed is the wall clock time spent in every phase of every day added
+// together. When nothing runs concurrently it's roughly Duration, otherwise
+// it's how long the run would have taken one phase at a time. It isn't CPU
+// time: a phase blocked on I/O or left waiting for a free core still counts.
+func (r Report) Summed() time.Duration {
+	var total Timings
+	for _, result := range r.Results {
+		total = total.Add(result.Timings)
+	}
+
+	return total.Load + total.PartOne + total.PartTwo
+}
+
+// Failed reports whether any of the days in the report failed.
+func (r Report) Failed() bool {
+	for _, result := range r.Results {
+		if result.Failed() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Report) benchmarked() bool {
+	for _, result := range r.Results {
+		if result.Bench != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Report) compared() bool {
+	for _, result := range r.Results {
+		if result.Comparison != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Formatter writes a Report out in one particular format.
+type Formatter func(io.Writer, Report) error
+
+// formatters maps the names accepted by -format onto the Formatter that
+// implements them. The box drawing table is the default since it's the one
+// meant for people, the rest are meant for other programs.
+var formatters = map[string]Formatter{
+	"table":    WriteTable,
+	"markdown": WriteMarkdown,
+	"csv":      WriteCSV,
+	"json":     WriteJSON,
+	"jsonl":    WriteJSONLines,
+}
+
+func formatNames() []string {
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func header(r Report) []string {
+	if r.benchmarked() {
+		columns := []string{"Day", "Name", "Phase", "Runs", "Min", "Median", "Mean", "P95", "Std Dev", "Allocs/Run", "Bytes/Run"}
+		if r.compared() {
+			columns = append(columns, "Median Ratio", "Allocs Ratio", "Regressed")
+		}
+
+		return columns
+	}
+
+	if r.Breakdown {
+		return []string{"Day", "Name", "Part One", "Part Two", "Status", "Load", "Part One Time", "Part Two Time", "Duration"}
+	}
+
+	return []string{"Day", "Name", "Part One", "Part Two", "Status", "Duration"}
+}
+
+// rows lays the report out the way people read it: one row per day with
+// a final row totalling up the whole run.
+func rows(r Report) [][]string {
+	if r.benchmarked() {
+		return benchRows(r)
+	}
+
+	var total Timings
+
+	out := make([][]string, 0, len(r.Results)+1)
+	for _, result := range r.Results {
+		row := []string{
+			fmt.Sprintf("Day %v", result.Day),
+			result.Name,
+			result.PartOne.String(),
+			result.PartTwo.String(),
+			result.Status(),
+		}
+
+		if r.Breakdown {
+			row = append(row, timings(result.Timings)...)
+		}
+
+		out = append(out, append(row, result.Duration.String()))
+		total = total.Add(result.Timings)
+	}
+
+	last := []string{"All Days", "", "", "", ""}
+	if r.Breakdown {
+		last = append(last, timings(total)...)
+	}
+
+	out = append(out, append(last, r.Duration.String()))
+
+	if r.Concurrent {
+		summed := make([]string, len(last)+1)
+		summed[0], summed[len(summed)-1] = "Summed Time", r.Summed().String()
+		out = append(out, summed)
+	}
+
+	return out
+}
+
+func timings(t Timings) []string {
+	return []string{t.Load.String(), t.PartOne.String(), t.PartTwo.String()}
+}
+
+// benchRows gives each benchmarked day one row per phase, and since the
+// allocation counts and baseline comparisons are made for whole runs
+// they're only filled in on the total.
+func benchRows(r Report) [][]string {
+	width := len(header(r))
+	compared := r.compared()
+
+	var out [][]string
+	for _, result := range r.Results {
+		day := fmt.Sprintf("Day %v", result.Day)
+
+		if result.Bench == nil {
+			msg := result.PartOne.String()
+			if result.PartOne.Err == nil {
+				msg = result.PartTwo.String()
+			}
+
+			row := make([]string, width)
+			row[0], row[1], row[2] = day, result.Name, msg
+
+			out = append(out, row)
+			continue
+		}
+
+		b := result.Bench
+		for i, phase := range []struct {
+			name  string
+			stats Stats
+		}{
+			{"Load", b.Load},
+			{"Part One", b.PartOne},
+			{"Part Two", b.PartTwo},
+			{"Total", b.Total},
+		} {
+			row := []string{
+				"",
+				"",
+				phase.name,
+				"",
+				phase.stats.Min.String(),
+				phase.stats.Median.String(),
+				phase.stats.Mean.String(),
+				phase.stats.P95.String(),
+				phase.stats.StdDev.String(),
+				"",
+				"",
+			}
+
+			if i == 0 {
+				row[0], row[1], row[3] = day, result.Name, strconv.Itoa(b.Runs)
+			}
+
+			if phase.name == "Total" {
+				row[9], row[10] = strconv.FormatUint(b.AllocsPerRun, 10), strconv.FormatUint(b.BytesPerRun, 10)
+			}
+
+			if compared {
+				row = append(row, "", "", "")
+				if c := result.Comparison; c != nil && phase.name == "Total" {
+					row[11] = fmt.Sprintf("%.2fx", c.MedianRatio)
+					row[12] = fmt.Sprintf("%.2fx", c.AllocsRatio)
+					if c.Regressed {
+						row[13] = "REGRESSED"
+					}
+				}
+			}
+
+			out = append(out, row)
+		}
+	}
+
+	return out
+}
+
+func WriteTable(w io.Writer, r Report) error {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header(r))
+	table.AppendBulk(rows(r))
+	table.SetHeaderAlignment(tablewriter.ALIGN_RIGHT)
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+
+	if _, err := fmt.Fprintln(w, "Advent of Code 2021!"); err != nil {
+		return err
+	}
+
+	if r.compared() {
+		caption := fmt.Sprintf("Compared against %s.", r.Baseline)
+		if r.Baseline == latest {
+			caption = "Compared against the latest saved benchmark of each day."
+		}
+
+		table.SetCaption(true, caption)
+	}
+
+	table.Render()
+
+	return nil
+}
+
+// WriteMarkdown renders the same table as WriteTable using GitHub flavored
+// markdown so that it can be pasted straight into a README.
+func WriteMarkdown(w io.Writer, r Report) error {
+	body := rows(r)
+	for _, row := range body {
+		for i, cell := range row {
+			row[i] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header(r))
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.SetBorders(tablewriter.Border{Left: true, Right: true})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(body)
+	table.Render()
+
+	return nil
+}
+
+// record is the flattened, machine readable form of a Result shared by
+// the JSON, JSON Lines and CSV formats. Durations are given both in raw
+// nanoseconds for programs and as a formatted string for people.
+type record struct {
+	Day               int    `json:"day"`
+	Name              string `json:"name"`
+	PartOne           string `json:"part_one"`
+	PartOneError      string `json:"part_one_error,omitempty"`
+	PartOneExpected   string `json:"part_one_expected,omitempty"`
+	PartOneStatus     string `json:"part_one_status"`
+	PartTwo           string `json:"part_two"`
+	PartTwoError      string `json:"part_two_error,omitempty"`
+	PartTwoExpected   string `json:"part_two_expected,omitempty"`
+	PartTwoStatus     string `json:"part_two_status"`
+	LoadDurationNS    int64  `json:"load_duration_ns"`
+	LoadDuration      string `json:"load_duration"`
+	PartOneDurationNS int64  `json:"part_one_duration_ns"`
+	PartOneDuration   string `json:"part_one_duration"`
+	PartTwoDurationNS int64  `json:"part_two_duration_ns"`
+	PartTwoDuration   string `json:"part_two_duration"`
+	DurationNS        int64  `json:"duration_ns"`
+	Duration          string `json:"duration"`
+
+	Bench    *benchRecord      `json:"bench,omitempty"`
+	Baseline *comparisonRecord `json:"baseline,omitempty"`
+}
+
+type comparisonRecord struct {
+	Revision    string  `json:"revision"`
+	MedianRatio float64 `json:"median_ratio"`
+	AllocsRatio float64 `json:"allocs_ratio"`
+	Regressed   bool    `json:"regressed"`
+}
+
+func newComparisonRecord(c *Comparison) *comparisonRecord {
+	if c == nil {
+		return nil
+	}
+
+	return &comparisonRecord{
+		Revision:    c.Revision,
+		MedianRatio: c.MedianRatio,
+		AllocsRatio: c.AllocsRatio,
+		Regressed:   c.Regressed,
+	}
+}
+
+type statsRecord struct {
+	MinNS    int64  `json:"min_ns"`
+	Min      string `json:"min"`
+	MedianNS int64  `json:"median_ns"`
+	Median   string `json:"median"`
+	MeanNS   int64  `json:"mean_ns"`
+	Mean     string `json:"mean"`
+	P95NS    int64  `json:"p95_ns"`
+	P95      string `json:"p95"`
+	StdDevNS int64  `json:"stddev_ns"`
+	StdDev   string `json:"stddev"`
+}
+
+func newStatsRecord(s Stats) statsRecord {
+	return statsRecord{
+		MinNS:    s.Min.Nanoseconds(),
+		Min:      s.Min.String(),
+		MedianNS: s.Median.Nanoseconds(),
+		Median:   s.Median.String(),
+		MeanNS:   s.Mean.Nanoseconds(),
+		Mean:     s.Mean.String(),
+		P95NS:    s.P95.Nanoseconds(),
+		P95:      s.P95.String(),
+		StdDevNS: s.StdDev.Nanoseconds(),
+		StdDev:   s.StdDev.String(),
+	}
+}
+
+// fields flattens the stats into CSV columns in the same order as
+// statsFields.
+func (s statsRecord) fields() []string {
+	return []string{
+		strconv.FormatInt(s.MinNS, 10),
+		s.Min,
+		strconv.FormatInt(s.MedianNS, 10),
+		s.Median,
+		strconv.FormatInt(s.MeanNS, 10),
+		s.Mean,
+		strconv.FormatInt(s.P95NS, 10),
+		s.P95,
+		strconv.FormatInt(s.StdDevNS, 10),
+		s.StdDev,
+	}
+}
+
+var statsFields = []string{"min_ns", "min", "median_ns", "median", "mean_ns", "mean", "p95_ns", "p95", "stddev_ns", "stddev"}
+
+type benchRecord struct {
+	Runs         int         `json:"runs"`
+	Load         statsRecord `json:"load"`
+	PartOne      statsRecord `json:"part_one"`
+	PartTwo      statsRecord `json:"part_two"`
+	Total        statsRecord `json:"total"`
+	AllocsPerRun uint64      `json:"allocs_per_run"`
+	BytesPerRun  uint64      `json:"bytes_per_run"`
+}
+
+func newBenchRecord(b *Benchmark) *benchRecord {
+	if b == nil {
+		return nil
+	}
+
+	return &benchRecord{
+		Runs:         b.Runs,
+		Load:         newStatsRecord(b.Load),
+		PartOne:      newStatsRecord(b.PartOne),
+		PartTwo:      newStatsRecord(b.PartTwo),
+		Total:        newStatsRecord(b.Total),
+		AllocsPerRun: b.AllocsPerRun,
+		BytesPerRun:  b.BytesPerRun,
+	}
+}
+
+func newRecord(r Result) record {
+	rec := record{
+		Day:               r.Day,
+		Name:              r.Name,
+		PartOne:           r.PartOne.Value,
+		PartOneExpected:   r.PartOne.Expected,
+		PartOneStatus:     r.PartOne.Status(),
+		PartTwo:           r.PartTwo.Value,
+		PartTwoExpected:   r.PartTwo.Expected,
+		PartTwoStatus:     r.PartTwo.Status(),
+		LoadDurationNS:    r.Timings.Load.Nanoseconds(),
+		LoadDuration:      r.Timings.Load.String(),
+		PartOneDurationNS: r.Timings.PartOne.Nanoseconds(),
+		PartOneDuration:   r.Timings.PartOne.String(),
+		PartTwoDurationNS: r.Timings.PartTwo.Nanoseconds(),
+		PartTwoDuration:   r.Timings.PartTwo.String(),
+		DurationNS:        r.Duration.Nanoseconds(),
+		Duration:          r.Duration.String(),
+		Bench:             newBenchRecord(r.Bench),
+		Baseline:          newComparisonRecord(r.Comparison),
+	}
+
+	if r.PartOne.Err != nil {
+		rec.PartOneError = r.PartOne.Err.Error()
+	}
+
+	if r.PartTwo.Err != nil {
+		rec.PartTwoError = r.PartTwo.Err.Error()
+	}
+
+	return rec
+}
+
+func WriteJSON(w io.Writer, r Report) error {
+	doc := struct {
+		Results          []record `json:"results"`
+		DurationNS       int64    `json:"duration_ns"`
+		Duration         string   `json:"duration"`
+		SummedDurationNS int64    `json:"summed_duration_ns"`
+		SummedDuration   string   `json:"summed_duration"`
+	}{
+		Results:          make([]record, len(r.Results)),
+		DurationNS:       r.Duration.Nanoseconds(),
+		Duration:         r.Duration.String(),
+		SummedDurationNS: r.Summed().Nanoseconds(),
+		SummedDuration:   r.Summed().String(),
+	}
+
+	for i, result := range r.Results {
+		doc.Results[i] = newRecord(result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(doc)
+}
+
+// WriteJSONLines writes one JSON object per day and nothing else, which
+// makes the output trivial to append to a log or stream into jq.
+func WriteJSONLines(w io.Writer, r Report) error {
+	enc := json.NewEncoder(w)
+	for _, result := range r.Results {
+		if err := enc.Encode(newRecord(result)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func WriteCSV(w io.Writer, r Report) error {
+	cw := csv.NewWriter(w)
+
+	columns := []string{
+		"day",
+		"name",
+		"part_one",
+		"part_one_error",
+		"part_one_expected",
+		"part_one_status",
+		"part_two",
+		"part_two_error",
+		"part_two_expected",
+		"part_two_status",
+		"load_duration_ns",
+		"load_duration",
+		"part_one_duration_ns",
+		"part_one_duration",
+		"part_two_duration_ns",
+		"part_two_duration",
+		"duration_ns",
+		"duration",
+	}
+
+	// Benchmark statistics don't nest in CSV, so every phase gets its
+	// own run of columns tacked onto the end.
+	bench := r.benchmarked()
+	if bench {
+		columns = append(columns, "runs")
+		for _, phase := range []string{"load", "part_one", "part_two", "total"} {
+			for _, field := range statsFields {
+				columns = append(columns, phase+"_"+field)
+			}
+		}
+
+		columns = append(columns, "allocs_per_run", "bytes_per_run")
+	}
+
+	compared := r.compared()
+	if compared {
+		columns = append(columns, "baseline_revision", "median_ratio", "allocs_ratio", "regressed")
+	}
+
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+
+	for _, result := range r.Results {
+		rec := newRecord(result)
+		row := []string{
+			strconv.Itoa(rec.Day),
+			rec.Name,
+			rec.PartOne,
+			rec.PartOneError,
+			rec.PartOneExpected,
+			rec.PartOneStatus,
+			rec.PartTwo,
+			rec.PartTwoError,
+			rec.PartTwoExpected,
+			rec.PartTwoStatus,
+			strconv.FormatInt(rec.LoadDurationNS, 10),
+			rec.LoadDuration,
+			strconv.FormatInt(rec.PartOneDurationNS, 10),
+			rec.PartOneDuration,
+			strconv.FormatInt(rec.PartTwoDurationNS, 10),
+			rec.PartTwoDuration,
+			strconv.FormatInt(rec.DurationNS, 10),
+			rec.Duration,
+		}
+
+		if bench {
+			b := rec.Bench
+			if b == nil {
+				b = new(benchRecord)
+			}
+
+			row = append(row, strconv.Itoa(b.Runs))
+			for _, stats := range []statsRecord{b.Load, b.PartOne, b.PartTwo, b.Total} {
+				row = append(row, stats.fields()...)
+			}
+
+			row = append(row, strconv.FormatUint(b.AllocsPerRun, 10), strconv.FormatUint(b.BytesPerRun, 10))
+		}
+
+		if compared {
+			c := rec.Baseline
+			if c == nil {
+				c = new(comparisonRecord)
+			}
+
+			row = append(
+				row,
+				c.Revision,
+				strconv.FormatFloat(c.MedianRatio, 'f', 4, 64),
+				strconv.FormatFloat(c.AllocsRatio, 'f', 4, 64),
+				strconv.FormatBool(c.Regressed),
+			)
+		}
+
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
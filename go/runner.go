@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+// Answer is the outcome of solving one part of a puzzle. A part that
+// wasn't asked for is left as the zero Answer.
+type Answer struct {
+	Value string
+	Err   error
+
+	// Expected and Verdict are filled in once the answer has been
+	// checked against a known good one.
+	Expected string
+	Verdict  Verdict
+}
+
+func (a Answer) String() string {
+	if a.TimedOut() {
+		return "TIMEOUT"
+	}
+
+	if a.Err != nil {
+		return "ERROR: " + a.Err.Error()
+	}
+
+	if a.Verdict == Fail {
+		return fmt.Sprintf("%s (want %s)", a.Value, a.Expected)
+	}
+
+	return a.Value
+}
+
+// TimedOut reports whether the part was given up on for taking longer
+// than the -timeout allowed.
+func (a Answer) TimedOut() bool {
+	return errors.Is(a.Err, context.DeadlineExceeded)
+}
+
+// Status is the part's Verdict, unless it never got to finish.
+func (a Answer) Status() string {
+	if a.TimedOut() {
+		return "timeout"
+	}
+
+	return a.Verdict.String()
+}
+
+// Timings breaks the time spent on a day down into its phases. Parsing
+// can easily dominate a day, day15 for one parses its grid twice, and
+// a single span would hide that behind the algorithms themselves.
+type Timings struct {
+	Load    time.Duration
+	PartOne time.Duration
+	PartTwo time.Duration
+}
+
+// Add returns the phase by phase sum of t and o.
+func (t Timings) Add(o Timings) Timings {
+	return Timings{
+		Load:    t.Load + o.Load,
+		PartOne: t.PartOne + o.PartOne,
+		PartTwo: t.PartTwo + o.PartTwo,
+	}
+}
+
+// Result collects everything we learned from running a single day.
+type Result struct {
+	Day     int
+	Name    string
+	PartOne Answer
+	PartTwo Answer
+	Timings Timings
+
+	// Duration is the wall clock time spent on the whole day, which
+	// includes the runner's own bookkeeping on top of the phases in
+	// Timings.
+	Duration time.Duration
+
+	// Bench holds the statistics gathered over repeated runs of the
+	// day when the runner is benchmarking, and is nil otherwise.
+	Bench *Benchmark
+
+	// Comparison measures Bench against a previously saved baseline
+	// when one was asked for and exists for this day.
+	Comparison *Comparison
+}
+
+// Failed reports whether either part of the day errored, including
+// a failure to load the input in the first place.
+func (r Result) Failed() bool {
+	return r.PartOne.Err != nil || r.PartTwo.Err != nil
+}
+
+// Status sums up the day the same way Answer.Status does for a part.
+func (r Result) Status() string {
+	if r.PartOne.TimedOut() || r.PartTwo.TimedOut() {
+		return "timeout"
+	}
+
+	return r.Verdict().String()
+}
+
+// fail records an error that happened before either part had a chance to
+// run. Without an input neither part has anything to work with, so both of
+// them share the blame.
+func (r *Result) fail(err error, opts Options) {
+	err = fmt.Errorf("load: %w", err)
+	if opts.RunsPart(1) {
+		r.PartOne.Err = err
+	}
+
+	if opts.RunsPart(2) {
+		r.PartTwo.Err = err
+	}
+}
+
+// NewSolution creates a Solution for the puzzle with any parameters given
+// for its day applied.
+func NewSolution(p registry.Puzzle, opts Options) (Solution, error) {
+	sol := p.New()
+	if err := registry.Configure(sol, opts.Params[p.Day]); err != nil {
+		return nil, err
+	}
+
+	return sol, nil
+}
+
+// RunDay reads the puzzle's input and solves it with a new Solution.
+func RunDay(p registry.Puzzle, opts Options) Result {
+	sol, err := NewSolution(p, opts)
+	if err != nil {
+		result := Result{
+			Day:  p.Day,
+			Name: p.Name(),
+		}
+
+		result.fail(err, opts)
+
+		return result
+	}
+
+	input, err := opts.ReadInput(p)
+	if err != nil {
+		result := Result{
+			Day:  p.Day,
+			Name: sol.Name(),
+		}
+
+		result.fail(err, opts)
+
+		return result
+	}
+
+	return Solve(p.Day, sol, input, opts)
+}
+
+// Solve loads input into sol and solves the parts selected in opts. Nothing
+// that goes wrong inside a day is allowed to escape it: errors and panics
+// alike are recorded against the part they came from so that one broken day
+// can't take the rest of the table down with it.
+//
+// The input is handed over already in memory so that reading it from disk
+// never counts towards the time spent in Load.
+//
+// With opts.Timeout set the whole day, Load included, has to finish within
+// it. Solutions that don't take a context themselves are wrapped with
+// registry.WithContext so that they can be abandoned all the same.
+func Solve(day int, sol Solution, input []byte, opts Options) (result Result) {
+	result.Day = day
+	result.Name = sol.Name()
+
+	ctx := context.Background()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	csol := registry.WithContext(sol)
+
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start)
+	}()
+
+	err := safely(func() error {
+		return csol.LoadContext(ctx, bytes.NewReader(input))
+	})
+	result.Timings.Load = time.Since(start)
+
+	if err != nil {
+		result.fail(err, opts)
+		return result
+	}
+
+	if opts.RunsPart(1) && opts.RunsPart(2) && opts.ParallelParts && concurrent(sol) {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result.PartTwo, result.Timings.PartTwo = solve(ctx, csol.PartTwoContext)
+		}()
+
+		result.PartOne, result.Timings.PartOne = solve(ctx, csol.PartOneContext)
+		wg.Wait()
+
+		return result
+	}
+
+	if opts.RunsPart(1) {
+		result.PartOne, result.Timings.PartOne = solve(ctx, csol.PartOneContext)
+	}
+
+	if opts.RunsPart(2) {
+		result.PartTwo, result.Timings.PartTwo = solve(ctx, csol.PartTwoContext)
+	}
+
+	return result
+}
+
+// concurrent reports whether sol has promised that its two parts can run
+// at the same time.
+func concurrent(sol Solution) bool {
+	c, ok := sol.(registry.Concurrent)
+	return ok && c.ConcurrentParts()
+}
+
+// RunAll runs every puzzle on a pool of opts.Workers goroutines, or
+// benchmarks them when that's what was asked for. Days finish in whatever
+// order they finish in, but the results always come back in the same order
+// as the puzzles that were passed in.
+func RunAll(puzzles []registry.Puzzle, opts Options) []Result {
+	run := RunDay
+	if opts.Benchmarking() {
+		run = BenchDay
+	}
+
+	workers := opts.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]Result, len(puzzles))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results[j] = run(puzzles[j], opts)
+			}
+		}()
+	}
+
+	for i := range puzzles {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+func solve(ctx context.Context, part func(context.Context) (string, error)) (Answer, time.Duration) {
+	var answer Answer
+
+	start := time.Now()
+	answer.Err = safely(func() error {
+		var err error
+		answer.Value, err = part(ctx)
+		return err
+	})
+
+	return answer, time.Since(start)
+}
+
+// safely calls f, converting any panic that escapes it into an error.
+func safely(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return f()
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+// maxInputSize caps the size of a posted puzzle input. The biggest real one
+// is a few tens of kilobytes, so this leaves plenty of room for anything a
+// person might reasonably want to throw at a solution.
+const maxInputSize = 10 << 20
+
+// Serve is the serve subcommand. It exposes every registered day over HTTP
+// so that other tools can solve inputs without shelling out to the runner:
+//
+//	GET  /days       lists the registered days
+//	POST /days/{n}   solves the input in the request body with day n
+//
+// Solving responds with the same JSON record for the day that -format json
+// writes out for each of its results. Adding ?part=1 or ?part=2 solves only
+// that part.
+func Serve(args []string) int {
+	fs := flag.NewFlagSet("advent serve", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	addr := fs.String("addr", "localhost:8080", "listen on `address`, which must be a loopback address")
+	timeout := fs.Duration("timeout", 30*time.Second, "give up on any day that takes longer than `duration` to solve")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: advent serve [flags]\n\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+
+		return exitUsage
+	}
+
+	if fs.NArg() > 0 {
+		fmt.Fprintf(fs.Output(), "unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		fs.Usage()
+		return exitUsage
+	}
+
+	if err := loopback(*addr); err != nil {
+		fmt.Fprintln(fs.Output(), err)
+		fs.Usage()
+		return exitUsage
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           NewServer(year, *timeout),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	fmt.Fprintf(os.Stderr, "serving on http://%s\n", *addr)
+	if err := server.ListenAndServe(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// loopback makes sure addr can only be reached from this machine. Nothing
+// about the server is hardened for anything else: anybody who can reach it
+// can keep every core busy for as long as the timeout allows.
+func loopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+
+	if host == "localhost" {
+		return nil
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+
+	return fmt.Errorf("refusing to listen on %q, which isn't a loopback address", addr)
+}
+
+type server struct {
+	year    int
+	timeout time.Duration
+}
+
+// NewServer returns the handler behind the serve subcommand for the given
+// year's puzzles. Each day gets at most timeout to solve an input, or as
+// long as it takes if timeout is zero.
+func NewServer(year int, timeout time.Duration) http.Handler {
+	s := &server{
+		year:    year,
+		timeout: timeout,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/days", s.list)
+	mux.HandleFunc("/days/", s.solve)
+
+	return mux
+}
+
+type dayRecord struct {
+	Day  int    `json:"day"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func (s *server) list(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s not allowed", r.Method))
+		return
+	}
+
+	puzzles := registry.Puzzles(s.year)
+
+	days := make([]dayRecord, len(puzzles))
+	for i, puzzle := range puzzles {
+		days[i] = dayRecord{
+			Day:  puzzle.Day,
+			Name: puzzle.Name(),
+			URL:  puzzle.URL(),
+		}
+	}
+
+	writeJSON(w, http.StatusOK, days)
+}
+
+func (s *server) solve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s not allowed", r.Method))
+		return
+	}
+
+	day, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/days/"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no such day %q", strings.TrimPrefix(r.URL.Path, "/days/")))
+		return
+	}
+
+	puzzle, ok := registry.Lookup(s.year, day)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no solution for day %v", day))
+		return
+	}
+
+	opts := Options{Timeout: s.timeout}
+	if part := r.URL.Query().Get("part"); part != "" {
+		opts.Part, err = strconv.Atoi(part)
+		if err != nil || opts.Part < 1 || opts.Part > 2 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("part must be 1 or 2, got %q", part))
+			return
+		}
+	}
+
+	input, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxInputSize))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+
+	// Whatever goes wrong inside the day itself is part of the result
+	// rather than a failed request, exactly as it would be in a report.
+	writeJSON(w, http.StatusOK, newRecord(Solve(day, puzzle.New(), input, opts)))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
@@ -0,0 +1,43 @@
+// Package sim drives the puzzles that play out one step at a time, like the
+// flashing octopuses of day 11 or the sea cucumbers of day 25. Each of them
+// only has to know how to take a single step and how much changed when it
+// did. How many steps to take, or when to stop, is the same loop every time
+// and lives here.
+package sim
+
+// Stepper is a simulation that moves forward a step at a time. Step reports
+// how many things changed along the way, whatever counts as a change for
+// that particular simulation: octopuses flashing, sea cucumbers moving.
+type Stepper interface {
+	Step() int
+}
+
+// Run takes n steps and adds up the changes from every one of them.
+func Run(s Stepper, n int) int {
+	var total int
+	for i := 0; i < n; i++ {
+		total += s.Step()
+	}
+
+	return total
+}
+
+// Until keeps stepping until done is happy with the changes from a step,
+// returning how many steps that took including the last one. Not every
+// simulation is guaranteed to get there, so it gives up after limit steps
+// and reports that it never did.
+func Until(s Stepper, limit int, done func(changes int) bool) (int, bool) {
+	for step := 1; step <= limit; step++ {
+		if done(s.Step()) {
+			return step, true
+		}
+	}
+
+	return limit, false
+}
+
+// Settled is a done for Until that stops at the first step where nothing
+// changed.
+func Settled(changes int) bool {
+	return changes == 0
+}
@@ -0,0 +1,36 @@
+package sim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countdown changes one less thing every step until it runs out.
+type countdown int
+
+func (c *countdown) Step() int {
+	if *c > 0 {
+		*c--
+	}
+
+	return int(*c)
+}
+
+func Test_Run(t *testing.T) {
+	c := countdown(5)
+	assert.Equal(t, 4+3+2, Run(&c, 3))
+	assert.Equal(t, countdown(2), c)
+}
+
+func Test_Until(t *testing.T) {
+	c := countdown(5)
+	steps, ok := Until(&c, 100, Settled)
+	assert.True(t, ok)
+	assert.Equal(t, 5, steps)
+
+	c = countdown(5)
+	steps, ok = Until(&c, 3, Settled)
+	assert.False(t, ok)
+	assert.Equal(t, 3, steps)
+}
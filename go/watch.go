@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/stntngo/advent-2021/go/registry"
+)
+
+// Watch solves the day all over again every time the file given with -input
+// changes, printing how the answers and timings moved since the last time.
+// It polls rather than relying on any platform's file notifications, which
+// is plenty for a file that changes whenever somebody hits save. Only the
+// input is watched: the solution is compiled into the runner, so there's no
+// picking up a change to its code without building the runner again. It
+// carries on until stop is closed, which for a nil stop means forever.
+func Watch(p registry.Puzzle, opts Options, answers Answers, out io.Writer, stop <-chan struct{}) {
+	ticker := time.NewTicker(opts.Poll)
+	defer ticker.Stop()
+
+	var (
+		prev    *Result
+		seen    os.FileInfo
+		missing bool
+	)
+
+	fmt.Fprintf(out, "watching %s, press ctrl-c to stop\n", opts.Input)
+
+	for {
+		info, err := os.Stat(opts.Input)
+		switch {
+		case err != nil:
+			// Plenty of editors save by writing a new file and
+			// renaming it over the old one, so the input going
+			// missing for a moment is nothing to give up over.
+			if !missing {
+				fmt.Fprintf(out, "%s %v\n", time.Now().Format("15:04:05"), err)
+				missing = true
+			}
+		case seen == nil || modified(seen, info):
+			missing = false
+			seen = info
+
+			result := RunDay(p, opts)
+			answers.Check(&result)
+
+			WriteDiff(out, prev, result, time.Now())
+			prev = &result
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func modified(before, after os.FileInfo) bool {
+	return !before.ModTime().Equal(after.ModTime()) || before.Size() != after.Size()
+}
+
+// WriteDiff writes out the result of a day alongside how it differs from
+// the previous result for the same day. With no previous result it just
+// writes out the result.
+func WriteDiff(w io.Writer, prev *Result, cur Result, now time.Time) error {
+	fmt.Fprintf(w, "\n%s Day %v: %s\n", now.Format("15:04:05"), cur.Day, cur.Name)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	row := func(label string, value interface{}, change string) {
+		if change == "" {
+			fmt.Fprintf(tw, "  %s\t%v\n", label, value)
+			return
+		}
+
+		fmt.Fprintf(tw, "  %s\t%v\t%s\n", label, value, change)
+	}
+
+	answer := func(label string, cur Answer, prev *Answer) {
+		change := ""
+		if prev != nil {
+			change = "unchanged"
+			if prev.String() != cur.String() {
+				change = "was " + prev.String()
+			}
+		}
+
+		row(label, cur, change)
+	}
+
+	timing := func(label string, cur time.Duration, prev *time.Duration) {
+		change := ""
+		if prev != nil && *prev > 0 {
+			change = fmt.Sprintf("%+.1f%% (was %v)", 100*float64(cur-*prev)/float64(*prev), *prev)
+		}
+
+		row(label, cur, change)
+	}
+
+	if prev == nil {
+		answer("part one", cur.PartOne, nil)
+		answer("part two", cur.PartTwo, nil)
+		timing("load time", cur.Timings.Load, nil)
+		timing("part one time", cur.Timings.PartOne, nil)
+		timing("part two time", cur.Timings.PartTwo, nil)
+		timing("duration", cur.Duration, nil)
+	} else {
+		answer("part one", cur.PartOne, &prev.PartOne)
+		answer("part two", cur.PartTwo, &prev.PartTwo)
+		timing("load time", cur.Timings.Load, &prev.Timings.Load)
+		timing("part one time", cur.Timings.PartOne, &prev.Timings.PartOne)
+		timing("part two time", cur.Timings.PartTwo, &prev.Timings.PartTwo)
+		timing("duration", cur.Duration, &prev.Duration)
+	}
+
+	return tw.Flush()
+}